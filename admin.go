@@ -0,0 +1,404 @@
+package cronger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	_maxBodySize = 1 << 20
+)
+
+type adminHandler struct {
+	c *Cronger
+}
+
+// NewAdminHandler returns the JSON API managing the jobs of the scheduler,
+// mount it under a prefix with http.StripPrefix:
+//
+//	GET    /jobs[?status=]         list jobs
+//	GET    /jobs/suspended         suspended jobs waiting for restore
+//	POST   /jobs                   add a job with a registered function
+//	POST   /jobs/cancel            cancel jobs by ids and function name
+//	PATCH  /jobs/{tag}             update a job
+//	DELETE /jobs/{tag}             remove a job
+//	POST   /jobs/{tag}/recover     run the job through the schedule
+//	POST   /jobs/{tag}/pause       pause a job
+//	POST   /jobs/{tag}/resume      resume a paused job
+//	POST   /jobs/{tag}/trigger     run the job once now
+func NewAdminHandler(c *Cronger) http.Handler {
+	return &adminHandler{c: c}
+}
+
+// CancelRequest is the body of POST /jobs/cancel.
+type CancelRequest struct {
+	IDs          []string `json:"ids"`
+	FunctionName string   `json:"function_name"`
+}
+
+// JobRequest is the body of POST /jobs and PATCH /jobs/{tag}, the fields match
+// Job. The tag of PATCH is taken from the path.
+type JobRequest struct {
+	Tag               string           `json:"tag"`
+	ID                string           `json:"id"`
+	Expression        string           `json:"expression"`
+	ExpressionFormat  ExpressionFormat `json:"expression_format"`
+	Location          string           `json:"location"`
+	RunAt             *time.Time       `json:"run_at"`
+	Interval          Duration         `json:"interval"`
+	IntervalName      string           `json:"interval_name"`
+	FunctionName      string           `json:"function_name"`
+	FunctionFields    FunctionFields   `json:"function_fields"`
+	Limit             uint             `json:"limit"`
+	Timeout           Duration         `json:"timeout"`
+	MaxAttempts       uint             `json:"max_attempts"`
+	Backoff           Backoff          `json:"backoff"`
+	BackoffDelay      Duration         `json:"backoff_delay"`
+	BackoffMax        Duration         `json:"backoff_max"`
+	Jitter            float64          `json:"jitter"`
+	Overlap           OverlapPolicy    `json:"overlap_policy"`
+	DependsOn         []string         `json:"depends_on"`
+	OnUpstreamFailure DependencyPolicy `json:"dependency_policy"`
+	Misfire           MisfirePolicy    `json:"misfire_policy"`
+	MisfireLimit      uint             `json:"misfire_limit"`
+}
+
+// Job returns the job of the request.
+func (r JobRequest) Job() Job {
+	return Job{
+		Tag:               r.Tag,
+		ID:                r.ID,
+		Expression:        r.Expression,
+		ExpressionFormat:  r.ExpressionFormat,
+		Location:          r.Location,
+		RunAt:             r.RunAt,
+		Interval:          time.Duration(r.Interval),
+		IntervalName:      r.IntervalName,
+		FunctionName:      r.FunctionName,
+		FunctionFields:    r.FunctionFields,
+		Limit:             r.Limit,
+		Timeout:           time.Duration(r.Timeout),
+		MaxAttempts:       r.MaxAttempts,
+		Backoff:           r.Backoff,
+		BackoffDelay:      time.Duration(r.BackoffDelay),
+		BackoffMax:        time.Duration(r.BackoffMax),
+		Jitter:            r.Jitter,
+		Overlap:           r.Overlap,
+		DependsOn:         r.DependsOn,
+		OnUpstreamFailure: r.OnUpstreamFailure,
+		Misfire:           r.Misfire,
+		MisfireLimit:      r.MisfireLimit,
+	}
+}
+
+// JobResponse is a job in the responses, with its schedule and retry state.
+type JobResponse struct {
+	JobRequest
+	FireCount         uint       `json:"fire_count"`
+	LastFireAt        *time.Time `json:"last_fire_at"`
+	NextFireAt        *time.Time `json:"next_fire_at"`
+	Attempt           uint       `json:"attempt"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at"`
+	Status            Status     `json:"status"`
+	StatusDescription string     `json:"status_description"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// NewJobResponse returns the job in the encoding of the admin API.
+func NewJobResponse(job Job) JobResponse {
+	return JobResponse{
+		JobRequest: JobRequest{
+			Tag:               job.Tag,
+			ID:                job.ID,
+			Expression:        job.Expression,
+			ExpressionFormat:  job.ExpressionFormat,
+			Location:          job.Location,
+			RunAt:             job.RunAt,
+			Interval:          Duration(job.Interval),
+			IntervalName:      job.IntervalName,
+			FunctionName:      job.FunctionName,
+			FunctionFields:    job.FunctionFields,
+			Limit:             job.Limit,
+			Timeout:           Duration(job.Timeout),
+			MaxAttempts:       job.MaxAttempts,
+			Backoff:           job.Backoff,
+			BackoffDelay:      Duration(job.BackoffDelay),
+			BackoffMax:        Duration(job.BackoffMax),
+			Jitter:            job.Jitter,
+			Overlap:           job.Overlap,
+			DependsOn:         job.DependsOn,
+			OnUpstreamFailure: job.OnUpstreamFailure,
+			Misfire:           job.Misfire,
+			MisfireLimit:      job.MisfireLimit,
+		},
+		FireCount:         job.FireCount,
+		LastFireAt:        job.LastFireAt,
+		NextFireAt:        job.NextFireAt,
+		Attempt:           job.Attempt,
+		NextAttemptAt:     job.NextAttemptAt,
+		Status:            job.Status,
+		StatusDescription: job.StatusDescription,
+		CreatedAt:         job.CreatedAt,
+	}
+}
+
+// RunResponse is a run of a job, encoded as the jobs of the admin API.
+type RunResponse struct {
+	ID         int64     `json:"id"`
+	Tag        string    `json:"tag"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Duration   Duration  `json:"duration"`
+	Status     Status    `json:"status"`
+	Error      string    `json:"error"`
+	Attempt    uint      `json:"attempt"`
+	Node       string    `json:"node"`
+}
+
+// NewRunResponse returns the run with snake_case fields and a duration string.
+func NewRunResponse(run Run) RunResponse {
+	return RunResponse{
+		ID:         run.ID,
+		Tag:        run.Tag,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Duration:   Duration(run.Duration),
+		Status:     run.Status,
+		Error:      run.Error,
+		Attempt:    run.Attempt,
+		Node:       run.Node,
+	}
+}
+
+// Duration is encoded as a string such as "1m30s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration %s: want a string such as \"1m30s\"", data)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("duration: %w", err)
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if path[0] != "jobs" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	switch len(path) {
+	case 1:
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:  h.jobs,
+			http.MethodPost: h.add,
+		})
+	case 2:
+		switch path[1] {
+		case "suspended":
+			h.route(w, r, map[string]http.HandlerFunc{http.MethodGet: h.suspended})
+		case "cancel":
+			h.route(w, r, map[string]http.HandlerFunc{http.MethodPost: h.cancel})
+		default:
+			tag := path[1]
+			h.route(w, r, map[string]http.HandlerFunc{
+				http.MethodPatch: func(w http.ResponseWriter, r *http.Request) { h.update(w, r, tag) },
+				http.MethodDelete: func(w http.ResponseWriter, r *http.Request) {
+					h.result(w, http.StatusNoContent, nil, h.c.Remove(tag))
+				},
+			})
+		}
+	case 3:
+		actions := map[string]func(string) error{
+			"recover": h.c.Recover,
+			"pause":   h.c.Pause,
+			"resume":  h.c.Resume,
+			"trigger": h.c.Trigger,
+		}
+		action, ok := actions[path[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.New("unknown action "+path[2]))
+			return
+		}
+		tag := path[1]
+		h.route(w, r, map[string]http.HandlerFunc{
+			http.MethodPost: func(w http.ResponseWriter, r *http.Request) {
+				h.result(w, http.StatusNoContent, nil, action(tag))
+			},
+		})
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (h *adminHandler) route(w http.ResponseWriter, r *http.Request, methods map[string]http.HandlerFunc) {
+	if handler, ok := methods[r.Method]; ok {
+		handler(w, r)
+		return
+	}
+
+	allow := make([]string, 0, len(methods))
+	for method := range methods {
+		allow = append(allow, method)
+	}
+	sort.Strings(allow)
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
+
+func (h *adminHandler) jobs(w http.ResponseWriter, r *http.Request) {
+	var (
+		jobs []Job
+		err  error
+	)
+	if status := r.URL.Query().Get(_status); len(status) != 0 {
+		jobs, err = h.c.JobsByStatus(Status(status))
+	} else {
+		jobs, err = h.c.Jobs()
+	}
+	if err != nil {
+		h.result(w, http.StatusOK, nil, err)
+		return
+	}
+	responses, err := h.responses(r.Context(), jobs)
+	h.result(w, http.StatusOK, responses, err)
+}
+
+func (h *adminHandler) suspended(w http.ResponseWriter, r *http.Request) {
+	responses, err := h.responses(r.Context(), h.c.SuspendJobs())
+	h.result(w, http.StatusOK, responses, err)
+}
+
+// responses converts the jobs with their upstream jobs, which are stored apart.
+func (h *adminHandler) responses(ctx context.Context, jobs []Job) ([]JobResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, _timeOut)
+	defer cancel()
+
+	dependencies, err := h.c.cfg.Repository.Dependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load dependencies: %w", err)
+	}
+	upstream := make(map[string][]string, len(dependencies))
+	for _, dependency := range dependencies {
+		upstream[dependency.Tag] = append(upstream[dependency.Tag], dependency.Upstream)
+	}
+
+	responses := make([]JobResponse, len(jobs))
+	for i, job := range jobs {
+		job.DependsOn = upstream[job.Tag]
+		responses[i] = NewJobResponse(job)
+	}
+	return responses, nil
+}
+
+func (h *adminHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	job := req.Job()
+	h.result(w, http.StatusCreated, NewJobResponse(job), h.c.AddJob(job))
+}
+
+func (h *adminHandler) update(w http.ResponseWriter, r *http.Request, tag string) {
+	var req JobRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	job := req.Job()
+	job.Tag = tag
+	h.result(w, http.StatusNoContent, nil, h.c.Update(job))
+}
+
+func (h *adminHandler) cancel(w http.ResponseWriter, r *http.Request) {
+	var req CancelRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	h.result(w, http.StatusNoContent, nil, h.c.SetStatusCancelled(req.IDs, req.FunctionName))
+}
+
+// result writes the value with the status or the error with its status code.
+func (h *adminHandler) result(w http.ResponseWriter, status int, value interface{}, err error) {
+	if err != nil {
+		code := errorStatus(err)
+		if code == http.StatusInternalServerError {
+			h.c.logger.Error("admin request", _error, err)
+		}
+		writeError(w, code, err)
+		return
+	}
+	writeJSON(w, status, value)
+}
+
+func errorStatus(err error) int {
+	var validation validator.ValidationErrors
+	switch {
+	case errors.As(err, &validation),
+		errors.Is(err, ErrInvalidSchedule),
+		errors.Is(err, ErrInvalidExpression),
+		errors.Is(err, ErrDependencyCycle),
+		errors.Is(err, ErrDependenciesUpdate),
+		errors.Is(err, ErrJobIntervalNotFound),
+		errors.Is(err, ErrHandlerNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrJobNotFound),
+		errors.Is(err, gocron.ErrJobNotFoundWithTag):
+		return http.StatusNotFound
+	case errors.Is(err, ErrJobNotPaused),
+		errors.Is(err, ErrJobPaused),
+		errors.Is(err, ErrDuplicateJob):
+		return http.StatusConflict
+	case errors.Is(err, ErrShutdown):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, _maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
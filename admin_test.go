@@ -0,0 +1,210 @@
+package cronger_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestAdminHandler(t *testing.T) {
+	repo := cronger.NewMemory()
+	registry := cronger.NewRegistry()
+	var runs atomic.Int32
+	require.NoError(t, registry.Register("test", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		runs.Add(1)
+		return nil
+	}))
+
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+		Registry:   registry,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", http.StripPrefix("/admin", cronger.NewAdminHandler(cr)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tag, id := uuid.NewString(), uuid.NewString()
+	job := `{"tag":"` + tag + `","id":"` + id + `","expression":"0 0 1 1 *",` +
+		`"function_name":"test","function_fields":[],"limit":3,"timeout":"1m"}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "add", method: http.MethodPost, path: "/jobs", body: job, status: http.StatusCreated},
+		{name: "add dependent", method: http.MethodPost, path: "/jobs",
+			body: `{"tag":"` + uuid.NewString() + `","id":"` + uuid.NewString() + `","depends_on":["` + tag + `"],` +
+				`"function_name":"test","function_fields":[],"limit":1,"dependency_policy":"run"}`,
+			status: http.StatusCreated},
+		{name: "add unknown function", method: http.MethodPost, path: "/jobs",
+			body: `{"tag":"` + uuid.NewString() + `","id":"` + uuid.NewString() + `","expression":"* * * * *",` +
+				`"function_name":"unknown","function_fields":[],"limit":1}`,
+			status: http.StatusBadRequest},
+		{name: "add invalid", method: http.MethodPost, path: "/jobs", body: `{"tag":"tag"}`, status: http.StatusBadRequest},
+		{name: "add go field names", method: http.MethodPost, path: "/jobs", body: `{"Tag":"` + uuid.NewString() + `"}`,
+			status: http.StatusBadRequest},
+		{name: "add nanoseconds", method: http.MethodPost, path: "/jobs",
+			body: `{"tag":"` + uuid.NewString() + `","timeout":60000000000}`, status: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, path: "/jobs", status: http.StatusOK},
+		{name: "list by status", method: http.MethodGet, path: "/jobs?status=working", status: http.StatusOK},
+		{name: "suspended", method: http.MethodGet, path: "/jobs/suspended", status: http.StatusOK},
+		{name: "update", method: http.MethodPatch, path: "/jobs/" + tag, body: `{"limit":5,"backoff_delay":"30s"}`, status: http.StatusNoContent},
+		{name: "update depends_on", method: http.MethodPatch, path: "/jobs/" + tag,
+			body: `{"depends_on":["` + uuid.NewString() + `"]}`, status: http.StatusBadRequest},
+		{name: "trigger", method: http.MethodPost, path: "/jobs/" + tag + "/trigger", status: http.StatusNoContent},
+		{name: "pause", method: http.MethodPost, path: "/jobs/" + tag + "/pause", status: http.StatusNoContent},
+		{name: "trigger paused", method: http.MethodPost, path: "/jobs/" + tag + "/trigger", status: http.StatusConflict},
+		{name: "resume", method: http.MethodPost, path: "/jobs/" + tag + "/resume", status: http.StatusNoContent},
+		{name: "resume not paused", method: http.MethodPost, path: "/jobs/" + tag + "/resume", status: http.StatusConflict},
+		{name: "recover", method: http.MethodPost, path: "/jobs/" + tag + "/recover", status: http.StatusNoContent},
+		{name: "unknown action", method: http.MethodPost, path: "/jobs/" + tag + "/start", status: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodGet, path: "/jobs/" + tag + "/pause", status: http.StatusMethodNotAllowed},
+		{name: "cancel", method: http.MethodPost, path: "/jobs/cancel",
+			body: `{"ids":["` + id + `"],"function_name":"test"}`, status: http.StatusNoContent},
+		{name: "remove", method: http.MethodDelete, path: "/jobs/" + tag, status: http.StatusNoContent},
+		{name: "trigger removed", method: http.MethodPost, path: "/jobs/" + tag + "/trigger", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+"/admin"+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if resp.StatusCode >= http.StatusBadRequest {
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.NotEmpty(t, body["error"])
+			}
+		})
+	}
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestAdminHandlerJobs(t *testing.T) {
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	task := func(context.Context) error { return nil }
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+		Timeout:        90 * time.Second,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: task}))
+	dependent := newDependencyJob(job.Tag)
+	require.NoError(t, cr.Add(cronger.Fields{Job: dependent, Task: task}))
+
+	tests := []struct {
+		name   string
+		status cronger.Status
+		count  int
+	}{
+		{name: "all", count: 2},
+		{name: "working", status: cronger.Working, count: 2},
+		{name: "failed", status: cronger.Failed, count: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs?status="+tt.status.String(), nil)
+			rec := httptest.NewRecorder()
+			cronger.NewAdminHandler(cr).ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var jobs []map[string]interface{}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&jobs))
+			require.Len(t, jobs, tt.count)
+
+			byTag := make(map[string]map[string]interface{}, len(jobs))
+			for _, job := range jobs {
+				byTag[job["tag"].(string)] = job
+			}
+			if tt.count == 0 {
+				return
+			}
+			assert.Equal(t, "1m30s", byTag[job.Tag]["timeout"])
+			assert.Equal(t, "working", byTag[job.Tag]["status"])
+			assert.Nil(t, byTag[job.Tag]["depends_on"])
+			assert.Equal(t, []interface{}{job.Tag}, byTag[dependent.Tag]["depends_on"])
+			assert.Equal(t, "0s", byTag[dependent.Tag]["timeout"])
+		})
+	}
+}
+
+func TestTriggerLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	var runs atomic.Int32
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          2,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}}))
+
+	// Triggered runs leave the limit to the schedule.
+	for i := int32(1); i <= 2; i++ {
+		require.NoError(t, cr.Trigger(job.Tag))
+		require.Eventually(t, func() bool {
+			history, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{Status: cronger.Done})
+			return err == nil && len(history) == int(i)
+		}, time.Second, 10*time.Millisecond)
+	}
+	assert.Equal(t, int32(2), runs.Load())
+	// The status of the run is stored after its history.
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, cr.Pause(job.Tag))
+	jobs, err := repo.Jobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, cronger.Paused, jobs[0].Status)
+	assert.Equal(t, job.Limit, jobs[0].Limit)
+	assert.Zero(t, jobs[0].FireCount)
+}
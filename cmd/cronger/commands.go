@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vladjong/cronger"
+)
+
+const (
+	_runsLimit = 10
+	_nextCount = 5
+)
+
+var errJobNotFound = errors.New("job not found")
+
+func list(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	status := flags.String("status", "", "job status")
+	function := flags.String("function", "", "function name")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		jobs []cronger.Job
+		err  error
+	)
+	if len(*status) != 0 {
+		jobs, err = repo.JobsByStatus(ctx, cronger.Status(*status))
+	} else {
+		jobs, err = repo.Jobs(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("list jobs: %w", err)
+	}
+	if err := withDependencies(ctx, repo, jobs); err != nil {
+		return err
+	}
+
+	filtered := make([]cronger.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if len(*function) == 0 || job.FunctionName == *function {
+			filtered = append(filtered, job)
+		}
+	}
+	return out.jobs(filtered)
+}
+
+// jobDetails is the output of show.
+type jobDetails struct {
+	Job  cronger.JobResponse   `json:"job"`
+	Runs []cronger.RunResponse `json:"runs"`
+}
+
+func show(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	if len(args) != 1 {
+		return fmt.Errorf("show <tag>: %w", errUsage)
+	}
+
+	job, err := findJob(ctx, repo, args[0])
+	if err != nil {
+		return err
+	}
+	runs, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{Limit: _runsLimit})
+	if err != nil {
+		return fmt.Errorf("runs of job %s: %w", job.Tag, err)
+	}
+	details := jobDetails{Job: cronger.NewJobResponse(job), Runs: make([]cronger.RunResponse, len(runs))}
+	for i, run := range runs {
+		details.Runs[i] = cronger.NewRunResponse(run)
+	}
+
+	return out.value(details, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "Tag:\t%s\n", job.Tag)
+		fmt.Fprintf(w, "ID:\t%s\n", job.ID)
+		fmt.Fprintf(w, "Function:\t%s\n", job.FunctionName)
+		fmt.Fprintf(w, "Schedule:\t%s\n", schedule(job))
+		fmt.Fprintf(w, "Status:\t%s\n", job.Status)
+		fmt.Fprintf(w, "Description:\t%s\n", job.StatusDescription)
+		fmt.Fprintf(w, "Limit:\t%d\n", job.Limit)
+		fmt.Fprintf(w, "Attempt:\t%d/%d\n", job.Attempt, job.MaxAttempts)
+		fmt.Fprintf(w, "Next attempt:\t%s\n", formatTime(job.NextAttemptAt))
+		fmt.Fprintf(w, "Last fire:\t%s\n", formatTime(job.LastFireAt))
+		fmt.Fprintf(w, "Next fire:\t%s\n", formatTime(job.NextFireAt))
+		fmt.Fprintf(w, "Created:\t%s\n", formatTime(&job.CreatedAt))
+
+		fmt.Fprintln(w, "\nSTARTED\tDURATION\tSTATUS\tATTEMPT\tNODE\tERROR")
+		for _, run := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", formatTime(&run.StartedAt), run.Duration,
+				run.Status, run.Attempt, run.Node, run.Error)
+		}
+	})
+}
+
+func cancel(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	flags := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	function := flags.String("function", "", "function name of the jobs")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if len(*function) == 0 || flags.NArg() == 0 {
+		return fmt.Errorf("cancel -function f <id>...: %w", errUsage)
+	}
+
+	tags, err := repo.SetStatusCancelled(ctx, flags.Args(), *function)
+	if err != nil {
+		return fmt.Errorf("cancel jobs: %w", err)
+	}
+	return out.tags(tags)
+}
+
+func remove(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	if len(args) == 0 {
+		return fmt.Errorf("remove <tag>...: %w", errUsage)
+	}
+
+	for _, tag := range args {
+		if _, err := findJob(ctx, repo, tag); err != nil {
+			return err
+		}
+		if err := repo.Remove(ctx, tag); err != nil {
+			return fmt.Errorf("remove job %s: %w", tag, err)
+		}
+	}
+	return out.tags(args)
+}
+
+// requeue marks the jobs suspended with the retry state reset, so an instance
+// restores them on start.
+func requeue(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	if len(args) == 0 {
+		return fmt.Errorf("requeue <tag>...: %w", errUsage)
+	}
+
+	for _, tag := range args {
+		if _, err := findJob(ctx, repo, tag); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, tag, map[string]interface{}{
+			"status":             cronger.Suspended.String(),
+			"status_description": "",
+			"attempt":            0,
+			"next_attempt_at":    nil,
+		}); err != nil {
+			return fmt.Errorf("requeue job %s: %w", tag, err)
+		}
+	}
+	return out.tags(args)
+}
+
+func migrate(ctx context.Context, db *sqlx.DB, opts []cronger.SqlxOption, args []string, out *output) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := flags.Uint("to", 0, "version to migrate up or down to, the latest by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	target := false
+	flags.Visit(func(f *flag.Flag) { target = target || f.Name == "to" })
+
+	var err error
+	if target {
+		err = cronger.MigrateTo(ctx, db, *to, opts...)
+	} else {
+		err = cronger.Migrate(ctx, db, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := cronger.MigrationVersion(ctx, db, opts...)
+	if err != nil {
+		return err
+	}
+	return out.value(map[string]uint{"version": version}, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "Version:\t%d\n", version)
+	})
+}
+
+func next(ctx context.Context, repo cronger.Repository, args []string, out *output) error {
+	flags := flag.NewFlagSet("next", flag.ContinueOnError)
+	n := flags.Int("n", _nextCount, "number of fire times")
+	loc := flags.String("loc", "Local", "time zone of the jobs without a location")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *n <= 0 {
+		return fmt.Errorf("next [-n count] <tag>: %w", errUsage)
+	}
+
+	job, err := findJob(ctx, repo, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(*loc)
+	if err != nil {
+		return fmt.Errorf("location: %w", err)
+	}
+
+	times, err := nextRuns(job, location, time.Now(), *n)
+	if err != nil {
+		return fmt.Errorf("next runs of job %s: %w", job.Tag, err)
+	}
+	if times == nil {
+		times = []time.Time{}
+	}
+	return out.value(times, func(w *tabwriter.Writer) {
+		for _, t := range times {
+			fmt.Fprintln(w, formatTime(&t))
+		}
+	})
+}
+
+// nextRuns returns the fire times of the job after from by its stored schedule.
+func nextRuns(job cronger.Job, loc *time.Location, from time.Time, n int) ([]time.Time, error) {
+	switch {
+	case job.RunAt != nil:
+		return []time.Time{*job.RunAt}, nil
+	case len(job.Expression) != 0:
+		if len(job.Location) != 0 {
+			var err error
+			if loc, err = time.LoadLocation(job.Location); err != nil {
+				return nil, err
+			}
+		}
+		return cronger.PreviewExpression(job.Expression, loc, from, n)
+	case job.Interval > 0:
+		start := from.Truncate(job.Interval).Add(job.Interval)
+		if job.NextFireAt != nil && job.NextFireAt.After(from) {
+			start = *job.NextFireAt
+		}
+		times := make([]time.Time, n)
+		for i := range times {
+			times[i] = start.Add(time.Duration(i) * job.Interval).In(loc)
+		}
+		return times, nil
+	case len(job.IntervalName) != 0:
+		return nil, fmt.Errorf("interval %q is resolved by the scheduler config", job.IntervalName)
+	default:
+		return nil, errors.New("job is triggered by its upstream jobs")
+	}
+}
+
+func findJob(ctx context.Context, repo cronger.Repository, tag string) (cronger.Job, error) {
+	jobs, err := repo.Jobs(ctx)
+	if err != nil {
+		return cronger.Job{}, fmt.Errorf("list jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if job.Tag == tag {
+			jobs := []cronger.Job{job}
+			if err := withDependencies(ctx, repo, jobs); err != nil {
+				return cronger.Job{}, err
+			}
+			return jobs[0], nil
+		}
+	}
+	return cronger.Job{}, fmt.Errorf("job %s: %w", tag, errJobNotFound)
+}
+
+// withDependencies sets the stored upstream jobs of the jobs.
+func withDependencies(ctx context.Context, repo cronger.Repository, jobs []cronger.Job) error {
+	dependencies, err := repo.Dependencies(ctx)
+	if err != nil {
+		return fmt.Errorf("list dependencies: %w", err)
+	}
+
+	upstream := make(map[string][]string)
+	for _, dependency := range dependencies {
+		upstream[dependency.Tag] = append(upstream[dependency.Tag], dependency.Upstream)
+	}
+	for i := range jobs {
+		jobs[i].DependsOn = upstream[jobs[i].Tag]
+	}
+	return nil
+}
+
+// schedule describes the schedule kind of the job.
+func schedule(job cronger.Job) string {
+	switch {
+	case job.RunAt != nil:
+		return "at " + formatTime(job.RunAt)
+	case len(job.Expression) != 0:
+		if len(job.Location) != 0 {
+			return job.Expression + " " + job.Location
+		}
+		return job.Expression
+	case job.Interval > 0:
+		return "every " + job.Interval.String()
+	case len(job.IntervalName) != 0:
+		return "every " + job.IntervalName
+	case len(job.DependsOn) != 0:
+		return "after " + strings.Join(job.DependsOn, ", ")
+	default:
+		return "after upstream jobs"
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,135 @@
+// Command cronger inspects and operates the jobs table of SqlxRepository.
+//
+//	cronger [flags] list [-status s] [-function f]
+//	cronger [flags] show <tag>
+//	cronger [flags] cancel -function f <id>...
+//	cronger [flags] remove <tag>...
+//	cronger [flags] requeue <tag>...
+//	cronger [flags] migrate [-to version]
+//	cronger [flags] next [-n count] <tag>
+//
+// The database is set by -dsn or the CRONGER_DSN environment variable. The tables and the
+// status type are set by the flags named after the SqlxOption they apply.
+//
+// cancel, remove and requeue write only the tables, the instances running the jobs do not
+// see the change and write their status back. Run them while the instances are stopped,
+// and use the admin API of a running instance otherwise.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/vladjong/cronger"
+)
+
+const (
+	_dsnEnv = "CRONGER_DSN"
+)
+
+var errUsage = errors.New("usage: cronger [-dsn dsn] [-schema schema] [-table table] [-runs-table table] " +
+	"[-dependencies-table table] [-migrations-table table] [-status-type type] [-format table|json] " +
+	"list|show|cancel|remove|requeue|migrate|next [args]")
+
+// optionFlags select the tables and the status type as the SqlxOption of the
+// same name.
+var optionFlags = []struct {
+	name   string
+	usage  string
+	option func(string) cronger.SqlxOption
+}{
+	{name: "schema", usage: "schema of the tables and the status type", option: cronger.WithSchema},
+	{name: "table", usage: "jobs table, the other tables are named after it", option: cronger.WithTable},
+	{name: "runs-table", usage: "runs table", option: cronger.WithRunsTable},
+	{name: "dependencies-table", usage: "dependencies table", option: cronger.WithDependenciesTable},
+	{name: "migrations-table", usage: "migrations table", option: cronger.WithMigrationsTable},
+	{name: "status-type", usage: "enum type of the job status", option: cronger.WithStatusType},
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("cronger", flag.ContinueOnError)
+	dsn := flags.String("dsn", os.Getenv(_dsnEnv), "PostgreSQL connection string, $"+_dsnEnv+" by default")
+	opts := addOptionFlags(flags)
+	format := flags.String("format", _formatTable, "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errUsage
+	}
+
+	out, err := newOutput(stdout, *format)
+	if err != nil {
+		return err
+	}
+
+	if len(*dsn) == 0 {
+		return fmt.Errorf("connect: -dsn or $%s is required", _dsnEnv)
+	}
+	db, err := sqlx.ConnectContext(ctx, "postgres", *dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	if command == "migrate" {
+		return migrate(ctx, db, opts(), args, out)
+	}
+
+	repo := cronger.NewSqlx(db, opts()...)
+	return execute(ctx, repo, command, args, out)
+}
+
+// addOptionFlags defines optionFlags, the returned function builds the options
+// of the flags that are set once they are parsed.
+func addOptionFlags(flags *flag.FlagSet) func() []cronger.SqlxOption {
+	values := make([]*string, len(optionFlags))
+	for i, f := range optionFlags {
+		values[i] = flags.String(f.name, "", f.usage)
+	}
+
+	return func() []cronger.SqlxOption {
+		var opts []cronger.SqlxOption
+		for i, f := range optionFlags {
+			if len(*values[i]) != 0 {
+				opts = append(opts, f.option(*values[i]))
+			}
+		}
+		return opts
+	}
+}
+
+// execute runs the command working with the repository.
+func execute(ctx context.Context, repo cronger.Repository, command string, args []string, out *output) error {
+	switch command {
+	case "list":
+		return list(ctx, repo, args, out)
+	case "show":
+		return show(ctx, repo, args, out)
+	case "cancel":
+		return cancel(ctx, repo, args, out)
+	case "remove":
+		return remove(ctx, repo, args, out)
+	case "requeue":
+		return requeue(ctx, repo, args, out)
+	case "next":
+		return next(ctx, repo, args, out)
+	default:
+		return fmt.Errorf("unknown command %q: %w", command, errUsage)
+	}
+}
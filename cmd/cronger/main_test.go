@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func newJob(status cronger.Status, function string) cronger.Job {
+	return cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 9 * * *",
+		Location:       "UTC",
+		FunctionName:   function,
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+		Status:         status,
+	}
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	failed := newJob(cronger.Failed, "report")
+	failed.Attempt = 3
+	working := newJob(cronger.Working, "sync")
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		tags    []string
+		status  cronger.Status
+		wantErr error
+	}{
+		{name: "list", command: "list", tags: []string{failed.Tag, working.Tag}},
+		{name: "list by status", command: "list", args: []string{"-status", "failed"}, tags: []string{failed.Tag}},
+		{name: "list by function", command: "list", args: []string{"-function", "sync"}, tags: []string{working.Tag}},
+		{name: "cancel", command: "cancel", args: []string{"-function", "sync", working.ID},
+			tags: []string{working.Tag}, status: cronger.Cancelled},
+		{name: "requeue", command: "requeue", args: []string{failed.Tag},
+			tags: []string{failed.Tag}, status: cronger.Suspended},
+		{name: "remove", command: "remove", args: []string{working.Tag}, tags: []string{working.Tag}},
+		{name: "remove unknown", command: "remove", args: []string{uuid.NewString()}, wantErr: errJobNotFound},
+		{name: "unknown command", command: "stop", wantErr: errUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			for _, job := range []cronger.Job{failed, working} {
+				require.NoError(t, repo.Add(ctx, job))
+			}
+
+			var buf bytes.Buffer
+			out, err := newOutput(&buf, _formatJSON)
+			require.NoError(t, err)
+
+			err = execute(ctx, repo, tt.command, tt.args, out)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var tags []string
+			if tt.command == "list" {
+				var jobs []cronger.JobResponse
+				require.NoError(t, json.Unmarshal(buf.Bytes(), &jobs))
+				for _, job := range jobs {
+					tags = append(tags, job.Tag)
+				}
+			} else {
+				require.NoError(t, json.Unmarshal(buf.Bytes(), &tags))
+			}
+			assert.ElementsMatch(t, tt.tags, tags)
+
+			if len(tt.status) != 0 {
+				jobs, err := repo.JobsByStatus(ctx, tt.status)
+				require.NoError(t, err)
+				require.Len(t, jobs, 1)
+				assert.Equal(t, tt.tags[0], jobs[0].Tag)
+				assert.Zero(t, jobs[0].Attempt)
+			}
+		})
+	}
+}
+
+func TestShowTable(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	job := newJob(cronger.Failed, "report")
+	require.NoError(t, repo.Add(ctx, job))
+	require.NoError(t, repo.AddRun(ctx, cronger.Run{
+		Tag:       job.Tag,
+		StartedAt: time.Now(),
+		Status:    cronger.Failed,
+		Error:     "connection refused",
+	}))
+
+	var buf bytes.Buffer
+	out, err := newOutput(&buf, _formatTable)
+	require.NoError(t, err)
+	require.NoError(t, execute(ctx, repo, "show", []string{job.Tag}, out))
+
+	assert.Contains(t, buf.String(), job.Tag)
+	assert.Contains(t, buf.String(), "0 9 * * * UTC")
+	assert.Contains(t, buf.String(), "connection refused")
+}
+
+func TestShowJSON(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	job := newJob(cronger.Working, "report")
+	job.Timeout = 90 * time.Second
+	require.NoError(t, repo.Add(ctx, job))
+	require.NoError(t, repo.AddRun(ctx, cronger.Run{
+		Tag:       job.Tag,
+		StartedAt: time.Now(),
+		Duration:  1500 * time.Millisecond,
+		Status:    cronger.Done,
+	}))
+
+	var buf bytes.Buffer
+	out, err := newOutput(&buf, _formatJSON)
+	require.NoError(t, err)
+	require.NoError(t, execute(ctx, repo, "show", []string{job.Tag}, out))
+
+	// The fields match the admin API.
+	var details struct {
+		Job  map[string]interface{}   `json:"job"`
+		Runs []map[string]interface{} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &details))
+	assert.Equal(t, job.Tag, details.Job["tag"])
+	assert.Equal(t, "report", details.Job["function_name"])
+	assert.Equal(t, "1m30s", details.Job["timeout"])
+	require.Len(t, details.Runs, 1)
+	assert.Equal(t, "1.5s", details.Runs[0]["duration"])
+	assert.Equal(t, "done", details.Runs[0]["status"])
+}
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := from.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		job     cronger.Job
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name: "expression",
+			job:  cronger.Job{Expression: "0 9 * * *", Location: "UTC"},
+			want: []time.Time{from.Add(23 * time.Hour), from.Add(47 * time.Hour)},
+		},
+		{
+			name: "interval",
+			job:  cronger.Job{Interval: 30 * time.Minute},
+			want: []time.Time{from.Add(30 * time.Minute), from.Add(time.Hour)},
+		},
+		{
+			name: "one-off",
+			job:  cronger.Job{RunAt: &at},
+			want: []time.Time{at},
+		},
+		{
+			name:    "dependent",
+			job:     cronger.Job{DependsOn: []string{uuid.NewString()}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, err := nextRuns(tt.job, time.UTC, from, 2)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, times, len(tt.want))
+			for i := range tt.want {
+				assert.True(t, tt.want[i].Equal(times[i]), "%s != %s", tt.want[i], times[i])
+			}
+		})
+	}
+}
+
+func TestOptionFlags(t *testing.T) {
+	flags := flag.NewFlagSet("cronger", flag.ContinueOnError)
+	opts := addOptionFlags(flags)
+	require.NoError(t, flags.Parse([]string{
+		"-table", "billing_jobs",
+		"-runs-table", "billing_history",
+		"-dependencies-table", "billing_edges",
+		"-migrations-table", "billing_versions",
+		"-status-type", "BILLING_STATUS",
+		"migrate",
+	}))
+	assert.Equal(t, []string{"migrate"}, flags.Args())
+
+	// Every statement is accepted and recorded.
+	var queries []string
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(
+		func(_, actual string) error {
+			queries = append(queries, actual)
+			return nil
+		})))
+	require.NoError(t, err)
+	db := sqlx.NewDb(mockDB, "postgres")
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := os.ReadDir("../../migration")
+	require.NoError(t, err)
+	mock.ExpectExec("lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("create").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select").WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	for range migrations {
+		mock.ExpectBegin()
+		mock.ExpectExec("migration").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("version").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	mock.ExpectExec("unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("create").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(migrations)))
+
+	var buf bytes.Buffer
+	out, err := newOutput(&buf, _formatJSON)
+	require.NoError(t, err)
+	require.NoError(t, migrate(context.Background(), db, opts(), nil, out))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	statements := strings.Join(queries, "\n")
+	for _, name := range []string{`"billing_jobs"`, `"billing_history"`, `"billing_edges"`, `"billing_versions"`, `"BILLING_STATUS"`} {
+		assert.Contains(t, statements, name)
+	}
+	assert.NotContains(t, statements, "CRONJOB_STATUS")
+}
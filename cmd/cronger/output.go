@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vladjong/cronger"
+)
+
+const (
+	_formatTable = "table"
+	_formatJSON  = "json"
+)
+
+// output writes the results as an aligned table or as JSON.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+func newOutput(w io.Writer, format string) (*output, error) {
+	switch format {
+	case _formatTable:
+		return &output{w: w}, nil
+	case _formatJSON:
+		return &output{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, want table or json", format)
+	}
+}
+
+// value writes v as JSON or writes the table, jobs and runs are encoded as in
+// the admin API.
+func (o *output) value(v interface{}, table func(w *tabwriter.Writer)) error {
+	if o.json {
+		encoder := json.NewEncoder(o.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func (o *output) jobs(jobs []cronger.Job) error {
+	responses := make([]cronger.JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = cronger.NewJobResponse(job)
+	}
+	return o.value(responses, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "TAG\tID\tFUNCTION\tSCHEDULE\tSTATUS\tLIMIT\tATTEMPT\tNEXT FIRE\tCREATED")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n", job.Tag, job.ID, job.FunctionName,
+				schedule(job), job.Status, job.Limit, job.Attempt, formatTime(job.NextFireAt),
+				formatTime(&job.CreatedAt))
+		}
+	})
+}
+
+func (o *output) tags(tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	return o.value(tags, func(w *tabwriter.Writer) {
+		for _, tag := range tags {
+			fmt.Fprintln(w, tag)
+		}
+	})
+}
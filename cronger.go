@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"os"
 	"reflect"
 	"sync"
 	"time"
@@ -17,6 +17,12 @@ import (
 
 var (
 	ErrJobIntervalNotFound = errors.New("job interval not found")
+	ErrFieldNotFound       = errors.New("function field not found")
+	ErrJobTimeout          = errors.New("job timeout exceeded")
+	ErrJobRemoved          = errors.New("job removed")
+	ErrJobCancelled        = errors.New("job cancelled")
+	ErrShutdown            = errors.New("cronger is shut down")
+	ErrInvalidSchedule     = errors.New("exactly one of expression, run time, interval or dependencies must be set")
 )
 
 var validate *validator.Validate
@@ -32,8 +38,30 @@ const (
 type Cronger struct {
 	cfg           *Config
 	schedule      *gocron.Scheduler
+	registry      *Registry
+	ctx           context.Context
 	mu            sync.Mutex
 	suspendedJobs map[string]Job
+	runID         uint64
+	running       map[string]map[uint64]context.CancelCauseFunc
+	retries       map[string]*time.Timer
+	node          string
+	logger        Logger
+	hooks         *hooks
+	// Jobs added to the schedule with their tasks and the last known status.
+	scheduled map[string]Fields
+	// Worker pool of the concurrency limits, nil without them.
+	pool *pool
+	// Gates of the jobs with an overlap policy.
+	gates map[string]*overlapGate
+	// Dependent jobs by upstream tag and the upstream statuses of their next run.
+	downstream map[string][]string
+	rounds     map[string]map[string]Status
+	// Runs of the scheduled jobs counted against their limits.
+	fired map[string]uint
+	// Runs in progress, Shutdown waits for them.
+	inflight sync.WaitGroup
+	closed   bool
 }
 
 type Config struct {
@@ -42,6 +70,26 @@ type Config struct {
 	Repository Repository
 	// Time interval for starting tasks.
 	JobIntervals map[string]time.Duration
+	// Handlers by function name, used to restore suspended jobs on start.
+	Registry *Registry
+	// Parent context of running tasks, cancelling it cancels all tasks.
+	Context context.Context
+	// Name of the instance stored in the run history, hostname by default.
+	Node string
+	// Run each occurrence of a job on a single instance sharing the repository.
+	DistributedLock bool
+	// Receives the errors of background work, the standard log package by default.
+	Logger Logger
+	// Callbacks on the job events.
+	Hooks *Hooks
+	// Maximum of tasks running at the same time, zero means no limit.
+	MaxConcurrent int
+	// Maximum of running tasks by function name.
+	MaxConcurrentByFunction map[string]int
+	// Size of the queue of runs waiting for the limits, 1000 by default.
+	QueueSize int
+	// Handling of a run when the queue is full, skip by default.
+	Overflow OverflowPolicy
 }
 
 type Job struct {
@@ -49,52 +97,147 @@ type Job struct {
 	Tag string `db:"tag" validate:"required,uuid"`
 	// ID of the object.
 	ID string `db:"id" validate:"required,uuid"`
-	// Expression in cron format.
-	Expression     string         `db:"expression" validate:"required,cron"`
+	// Expression in cron format, empty for one-off and interval jobs.
+	Expression string `db:"expression"`
+	// Format of the expression, detected by its fields when empty.
+	ExpressionFormat ExpressionFormat `db:"expression_format" validate:"omitempty,oneof=standard seconds descriptor"`
+	// IANA time zone of the expression, Config.Loc when empty.
+	Location string `db:"location" validate:"omitempty,timezone"`
+	// Time of a one-off job.
+	RunAt *time.Time `db:"run_at"`
+	// Period of an interval job, either a duration or a name in Config.JobIntervals.
+	Interval       time.Duration  `db:"interval" validate:"gte=0"`
+	IntervalName   string         `db:"interval_name"`
 	FunctionName   string         `db:"function_name" validate:"required"`
 	FunctionFields FunctionFields `db:"function_fields" validate:"required"`
 	// Limit run job.
-	Limit             uint      `db:"limit" validate:"required,gte=0,lte=100"`
-	Status            Status    `db:"status"`
-	StatusDescription string    `db:"status_description"`
-	CreatedAt         time.Time `db:"created_at" goqu:"skipupdate"`
+	Limit uint `db:"limit" validate:"required,gte=0,lte=100"`
+	// Runs counted against Limit, retries are not counted.
+	FireCount uint `db:"fire_count"`
+	// Maximum runtime of a single run, zero means no limit.
+	Timeout time.Duration `db:"timeout" validate:"gte=0"`
+	// Retry policy of a failed run, zero MaxAttempts disables retries.
+	MaxAttempts  uint          `db:"max_attempts" validate:"gte=0,lte=100"`
+	Backoff      Backoff       `db:"backoff" validate:"omitempty,oneof=fixed exponential"`
+	BackoffDelay time.Duration `db:"backoff_delay" validate:"gte=0"`
+	BackoffMax   time.Duration `db:"backoff_max" validate:"gte=0"`
+	// Random part added to the backoff delay, a fraction from 0 to 1.
+	Jitter float64 `db:"jitter" validate:"gte=0,lte=1"`
+	// Handling of an occurrence while the previous run is executing, allow by default.
+	Overlap OverlapPolicy `db:"overlap_policy" validate:"omitempty,oneof=allow skip queue replace"`
+	// Tags of the jobs that trigger this one when all of them finish, such a job
+	// has no schedule of its own.
+	DependsOn []string `db:"-"`
+	// Handling of the run when an upstream job does not finish with done, skip by default.
+	OnUpstreamFailure DependencyPolicy `db:"dependency_policy" validate:"omitempty,oneof=skip fail run"`
+	// Handling of the occurrences missed while the job was suspended, skip by default.
+	Misfire MisfirePolicy `db:"misfire_policy" validate:"omitempty,oneof=skip run_once run_all"`
+	// Maximum of missed runs with run_all, zero means 100.
+	MisfireLimit uint `db:"misfire_limit" validate:"gte=0,lte=1000"`
+	// Scheduled time of the last and the next occurrence.
+	LastFireAt *time.Time `db:"last_fire_at"`
+	NextFireAt *time.Time `db:"next_fire_at"`
+	// Occurrence claimed by the distributed lock, written by TryLock only.
+	FiredAt *time.Time `db:"fired_at" goqu:"skipinsert,skipupdate"`
+	// Retry state of the last failed run.
+	Attempt           uint       `db:"attempt"`
+	NextAttemptAt     *time.Time `db:"next_attempt_at"`
+	Status            Status     `db:"status"`
+	StatusDescription string     `db:"status_description"`
+	CreatedAt         time.Time  `db:"created_at" goqu:"skipinsert,skipupdate"`
 }
 
 func (j Job) CheckUpdate() error {
 	if err := validate.Var(j.Tag, "required,uuid"); err != nil {
 		return fmt.Errorf("validate: %w", err)
 	}
+	if len(j.DependsOn) != 0 {
+		return fmt.Errorf("validate: %w", ErrDependenciesUpdate)
+	}
 	if len(j.ID) != 0 {
 		if err := validate.Var(j.ID, "uuid"); err != nil {
 			return fmt.Errorf("validate: %w", err)
 		}
 	}
+	if err := validate.Var(j.Location, "omitempty,timezone"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
 	if len(j.Expression) != 0 {
-		if err := validate.Var(j.Expression, "cron"); err != nil {
+		if _, err := j.Format(); err != nil {
 			return fmt.Errorf("validate: %w", err)
 		}
 	}
 	if err := validate.Var(j.Limit, "gte=0,lte=100"); err != nil {
 		return fmt.Errorf("validate: %w", err)
 	}
+	if err := validate.Var(j.Interval, "gte=0"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	if err := validate.Var(j.Timeout, "gte=0"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	if err := validate.Var(j.MaxAttempts, "gte=0,lte=100"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	if err := validate.Var(j.Backoff, "omitempty,oneof=fixed exponential"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	if err := validate.Var(j.Jitter, "gte=0,lte=1"); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
 	return nil
 }
 
 type Fields struct {
 	Job `validate:"required"`
 
-	Task func() error `validate:"required"`
+	Task func(ctx context.Context) error `validate:"required"`
 }
 
 type FunctionFields []interface{}
 
+// Scan keeps each field encoded as json.RawMessage until it is decoded
+// with its real type by Decode or Payload.
+//
+// Breaking change: stored fields used to be scanned into decoded values,
+// so type assertions such as fields[0].(string) on jobs loaded from the
+// repository now fail, decode them with Decode instead.
 func (f *FunctionFields) Scan(value interface{}) error {
 	bytes, ok := value.([]byte)
 	if !ok {
 		return fmt.Errorf("failed to cast value to []byte: %v", value)
 	}
 
-	return json.Unmarshal(bytes, &f)
+	var raw []json.RawMessage
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return err
+	}
+
+	fields := make(FunctionFields, len(raw))
+	for i := range raw {
+		fields[i] = raw[i]
+	}
+	*f = fields
+	return nil
+}
+
+func (f FunctionFields) Decode(i int, v interface{}) error {
+	if i < 0 || i >= len(f) {
+		return fmt.Errorf("decode field %d: %w", i, ErrFieldNotFound)
+	}
+
+	data, ok := f[i].(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(f[i]); err != nil {
+			return fmt.Errorf("encode field %d: %w", i, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode field %d: %w", i, err)
+	}
+	return nil
 }
 
 func (f FunctionFields) Value() (driver.Value, error) {
@@ -109,6 +252,9 @@ const (
 	Done      Status = "done"
 	Failed    Status = "failed"
 	Cancelled Status = "cancelled"
+	Retrying  Status = "retrying"
+	Paused    Status = "paused"
+	Skipped   Status = "skipped"
 )
 
 func (s Status) String() string {
@@ -123,20 +269,59 @@ func New(cfg *Config) (*Cronger, error) {
 	schedule := gocron.NewScheduler(cfg.Loc)
 	schedule.TagsUnique()
 
+	registry := cfg.Registry
+	if registry == nil {
+		registry = NewRegistry()
+	}
+
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	node := cfg.Node
+	if len(node) == 0 {
+		node, _ = os.Hostname()
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = defaultLogger()
+	}
+
 	c := &Cronger{
-		cfg:      cfg,
-		schedule: schedule,
+		cfg:        cfg,
+		schedule:   schedule,
+		registry:   registry,
+		ctx:        ctx,
+		running:    make(map[string]map[uint64]context.CancelCauseFunc),
+		retries:    make(map[string]*time.Timer),
+		node:       node,
+		logger:     logger,
+		hooks:      newHooks(cfg.Hooks, logger),
+		scheduled:  make(map[string]Fields),
+		gates:      make(map[string]*overlapGate),
+		downstream: make(map[string][]string),
+		rounds:     make(map[string]map[string]Status),
+		fired:      make(map[string]uint),
+		pool:       newPool(cfg),
 	}
 
 	if err := c.setSuspendJob(); err != nil {
 		return nil, err
 	}
 
-	if _, err := schedule.Cron("*/1 * * * *").Do(c.jobUpdateStatusDone); err != nil {
+	if _, err := schedule.Cron(_schedulerTimezone + "*/1 * * * *").Do(c.jobUpdateStatusDone); err != nil {
 		return nil, err
 	}
 
 	schedule.StartAsync()
+
+	if cfg.Registry != nil {
+		if err := c.Restore(); err != nil {
+			c.logger.Error("restore jobs", _error, err)
+		}
+	}
 	return c, nil
 }
 
@@ -162,6 +347,7 @@ func (c *Cronger) setSuspendJob() error {
 func (c *Cronger) jobUpdateStatusDone() {
 	data, err := c.Jobs()
 	if err != nil {
+		c.logger.Error("load jobs", _error, err)
 		return
 	}
 
@@ -182,13 +368,15 @@ func (c *Cronger) jobUpdateStatusDone() {
 			continue
 		}
 
-		if int(data.Limit) != runCount || data.Limit == Unlimited {
+		if int(data.Limit) != runCount || data.Limit == Unlimited || data.Status == Retrying {
 			continue
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
 		if err := c.cfg.Repository.UpdateStatus(ctx, data.Tag, Done); err != nil {
-			log.Println(err)
+			c.logger.Error("update job status", jobAttrs(data, _status, Done, _error, err)...)
+		} else {
+			c.changeStatus(data, Done)
 		}
 		cancel()
 	}
@@ -205,6 +393,17 @@ func (c *Cronger) Jobs() ([]Job, error) {
 	return jobs, nil
 }
 
+func (c *Cronger) JobsByStatus(status Status) ([]Job, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	jobs, err := c.cfg.Repository.JobsByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
 func (c *Cronger) SuspendJobs() []Job {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -216,6 +415,16 @@ func (c *Cronger) SuspendJobs() []Job {
 	return jobs
 }
 
+func (c *Cronger) Restore() error {
+	var errs []error
+	for _, job := range c.SuspendJobs() {
+		if err := c.AddJob(job); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", job.Tag, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *Cronger) Recover(tag string) error {
 	if err := c.schedule.RunByTag(tag); err != nil {
 		return err
@@ -223,35 +432,221 @@ func (c *Cronger) Recover(tag string) error {
 	return nil
 }
 
+// Trigger runs the scheduled job once now, the run does not count against
+// the limit and follows the overlap policy and the concurrency limits.
+func (c *Cronger) Trigger(tag string) error {
+	if c.isClosed() {
+		return ErrShutdown
+	}
+
+	c.mu.Lock()
+	fields, ok := c.scheduled[tag]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("trigger job %s: %w", tag, ErrJobNotFound)
+	}
+	if fields.Status == Paused {
+		return fmt.Errorf("trigger job %s: %w", tag, ErrJobPaused)
+	}
+
+	job := fields.Job
+	job.Attempt = 0
+	job.NextAttemptAt = nil
+	go c.fire(job, fields.Task, time.Now(), false)
+	return nil
+}
+
 func (c *Cronger) Add(in Fields) error {
+	if c.isClosed() {
+		return ErrShutdown
+	}
+	if in.RunAt != nil {
+		// One-off jobs run exactly once.
+		in.Limit = 1
+	}
 	if err := validate.Struct(&in); err != nil {
 		return fmt.Errorf("validate: %w", err)
 	}
+	if err := c.resolveDependencies(&in.Job); err != nil {
+		return err
+	}
 
 	job := in.Job
+	if len(job.Expression) != 0 {
+		format, err := job.Format()
+		if err != nil {
+			return fmt.Errorf("validate: %w", err)
+		}
+		job.ExpressionFormat = format
+	}
+	if err := checkSchedule(job); err != nil {
+		return err
+	}
 	job.Status = Working
+	if job.NextAttemptAt != nil {
+		job.Status = Retrying
+	} else if job.Limit != Unlimited && job.remaining() == 0 {
+		// The last run finished before the job was suspended.
+		job.Status = Done
+	}
 
-	schedule := c.schedule.Cron(job.Expression).Tag(job.Tag)
+	missed, err := c.missed(job, time.Now())
+	if err != nil {
+		return fmt.Errorf("misfire: %w", err)
+	}
+	if len(missed) != 0 {
+		job.LastFireAt = &missed[len(missed)-1]
+	}
+
+	// Scheduled runs start their own retries, the pending one is restored below.
+	scheduled := job
+	scheduled.Attempt = 0
+	scheduled.NextAttemptAt = nil
+
+	// Missed runs count against the limit, the schedule is not needed
+	// when they use all of it.
+	limited := job
 	if job.Limit != Unlimited {
-		schedule.LimitRunsTo(int(job.Limit))
+		limited.Limit = job.remaining() - uint(len(missed))
 	}
-	if _, err := schedule.Do(func() {
-		c.Template(job, in.Task)
-	}); err != nil {
-		return fmt.Errorf("create job: %w", err)
+	job.NextFireAt = nil
+	// Dependent jobs are triggered by their upstream jobs.
+	if len(job.DependsOn) == 0 && (job.Limit == Unlimited || limited.Limit != 0) {
+		schedule, err := c.newSchedule(limited)
+		if err != nil {
+			return err
+		}
+		gj, err := schedule.DoWithJobDetails(func(gj gocron.Job) {
+			if c.isClosed() {
+				return
+			}
+			scheduledAt := gj.LastRun()
+			if job.RunAt != nil {
+				scheduledAt = *job.RunAt
+			}
+			c.recordFire(scheduled, scheduledAt)
+			c.fire(scheduled, in.Task, scheduledAt, true)
+		})
+		if err != nil {
+			return fmt.Errorf("create job: %w", err)
+		}
+		if next := gj.NextRun(); !next.IsZero() {
+			job.NextFireAt = &next
+		}
 	}
 
 	if err := c.add(job); err != nil {
-		if err := c.schedule.RemoveByTag(job.Tag); err != nil {
+		if err := c.schedule.RemoveByTag(job.Tag); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
 			return fmt.Errorf("remove job: %w", err)
 		}
 		return err
 	}
+	if len(job.DependsOn) != 0 {
+		if err := c.setDependencies(job); err != nil {
+			return err
+		}
+	}
+
+	if job.NextAttemptAt != nil {
+		c.scheduleRetry(job, *job.NextAttemptAt, in.Task)
+	}
 
 	c.deleteSuspendJob(in.Tag)
+
+	c.mu.Lock()
+	c.scheduled[job.Tag] = in
+	// Runs that finished while the job was added are counted already.
+	c.fired[job.Tag] += job.FireCount
+	c.mu.Unlock()
+	c.changeStatus(in.Job, job.Status)
+	c.hooks.scheduled(job)
+
+	if len(missed) != 0 {
+		go c.catchUp(scheduled, in.Task, missed)
+	}
+	return nil
+}
+
+// checkSchedule checks that the job has exactly one kind of schedule,
+// dependent jobs have none.
+func checkSchedule(job Job) error {
+	kinds := 0
+	for _, set := range []bool{len(job.Expression) != 0, job.RunAt != nil, job.Interval != 0, len(job.IntervalName) != 0} {
+		if set {
+			kinds++
+		}
+	}
+	if len(job.DependsOn) != 0 {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("validate: %w", ErrInvalidSchedule)
+	}
 	return nil
 }
 
+// newSchedule returns the scheduler with the job to set a function.
+func (c *Cronger) newSchedule(job Job) (*gocron.Scheduler, error) {
+	var schedule *gocron.Scheduler
+	switch {
+	case job.RunAt != nil:
+		schedule = c.schedule.Every(1).Day().Tag(job.Tag).LimitRunsTo(1)
+		if job.RunAt.After(time.Now()) {
+			return schedule.StartAt(*job.RunAt), nil
+		}
+		// The time was missed while the job was suspended.
+		return schedule.StartImmediately(), nil
+	case job.ExpressionFormat == FormatSeconds:
+		schedule = c.schedule.CronWithSeconds(job.schedulerSpec()).Tag(job.Tag)
+	case len(job.Expression) != 0:
+		schedule = c.schedule.Cron(job.schedulerSpec()).Tag(job.Tag)
+	default:
+		interval, err := c.interval(job)
+		if err != nil {
+			return nil, err
+		}
+		// Runs are aligned to multiples of the interval, so the instances
+		// sharing the repository fire at the same time.
+		schedule = c.schedule.Every(interval).Tag(job.Tag).
+			StartAt(time.Now().Truncate(interval).Add(interval))
+	}
+
+	if job.Limit != Unlimited {
+		schedule.LimitRunsTo(int(job.Limit))
+	}
+	return schedule, nil
+}
+
+func (c *Cronger) interval(job Job) (time.Duration, error) {
+	if len(job.IntervalName) == 0 {
+		return job.Interval, nil
+	}
+
+	interval, err := c.JobInterval(job.IntervalName)
+	if err != nil {
+		return 0, fmt.Errorf("interval %q: %w", job.IntervalName, err)
+	}
+	return interval, nil
+}
+
+func (c *Cronger) Register(name string, handler Handler) error {
+	return c.registry.Register(name, handler)
+}
+
+func (c *Cronger) AddJob(job Job) error {
+	handler, err := c.registry.Handler(job.FunctionName)
+	if err != nil {
+		return err
+	}
+
+	return c.Add(Fields{
+		Job: job,
+		Task: func(ctx context.Context) error {
+			return handler(ctx, job, job.FunctionFields)
+		},
+	})
+}
+
 func (c *Cronger) add(in Job) error {
 	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
 	defer cancel()
@@ -268,6 +663,9 @@ func (c *Cronger) deleteSuspendJob(tag string) {
 	delete(c.suspendedJobs, tag)
 }
 
+// GetExpression returns the yearly cron expression of the time.
+//
+// Deprecated: the expression repeats every year, use AddAt for one-off jobs.
 func (c *Cronger) GetExpression(time time.Time) string {
 	_, month, day := time.Date()
 	hour, minute, _ := time.Clock()
@@ -292,9 +690,14 @@ func (c *Cronger) SetStatusCancelled(ids []string, functionName string) error {
 	}
 
 	for _, tag := range tags {
-		if err := c.schedule.RemoveByTag(tag); err != nil {
+		// Dependent jobs and jobs with their runs used have no gocron entry.
+		if err := c.schedule.RemoveByTag(tag); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
 			return fmt.Errorf("SuspendJobs in schedule: %w", err)
 		}
+		c.stopRetry(tag)
+		c.cancelRuns(tag, ErrJobCancelled)
+		c.changeStatus(Job{Tag: tag, FunctionName: functionName, Status: Working}, Cancelled)
+		c.deleteScheduled(tag)
 	}
 	return nil
 }
@@ -307,42 +710,274 @@ func (c *Cronger) Remove(tag string) error {
 		return err
 	}
 
-	if err := c.schedule.RemoveByTag(tag); err != nil {
+	// Dependent jobs and jobs with their runs used have no gocron entry.
+	if err := c.schedule.RemoveByTag(tag); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
 		return fmt.Errorf("remove job: %w", err)
 	}
 
+	c.stopRetry(tag)
+	c.cancelRuns(tag, ErrJobRemoved)
 	c.deleteSuspendJob(tag)
+	c.deleteScheduled(tag)
 	return nil
 }
 
+// Update stores the set fields of the job, the scheduled job takes them when it
+// is added again by Resume or on restart.
 func (c *Cronger) Update(in Job) error {
 	if err := in.CheckUpdate(); err != nil {
 		return fmt.Errorf("update: %w", err)
 	}
-	value := structToMap(in)
+	if len(in.Expression) != 0 {
+		// The format is stored with the expression it was detected from.
+		in.ExpressionFormat, _ = in.Format()
+	}
+	return c.update(in.Tag, structToMap(in))
+}
+
+func (c *Cronger) update(tag string, value map[string]interface{}) error {
 	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
 	defer cancel()
 
-	if err := c.cfg.Repository.Update(ctx, in.Tag, value); err != nil {
+	if err := c.cfg.Repository.Update(ctx, tag, value); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Cronger) Template(job Job, fnc func() error) {
-	if err := fnc(); err != nil {
-		job.Status = Failed
-		job.StatusDescription = err.Error()
-		if err := c.Update(job); err != nil {
-			log.Printf("set done: %v\n", err)
+// fire runs the occurrence, a counted one uses a run of the limit.
+func (c *Cronger) fire(job Job, fnc func(ctx context.Context) error, scheduledAt time.Time, counted bool) {
+	if c.isClosed() {
+		return
+	}
+	if c.cfg.DistributedLock {
+		ok, err := c.tryLock(job.Tag, scheduledAt)
+		if err != nil {
+			c.logger.Error("lock job", jobAttrs(job, _scheduledAt, scheduledAt, _error, err)...)
+			return
+		}
+		if !ok {
+			c.logger.Debug("job run claimed by another node", jobAttrs(job, _scheduledAt, scheduledAt)...)
+			return
+		}
+	}
+	c.runExclusive(job, fnc, scheduledAt, counted)
+}
+
+func (c *Cronger) tryLock(tag string, scheduledAt time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	return c.cfg.Repository.TryLock(ctx, tag, scheduledAt.Truncate(time.Microsecond))
+}
+
+// Template runs the task of the job and stores the result, the run counts
+// against the limit unless it is a retry.
+func (c *Cronger) Template(job Job, fnc func(ctx context.Context) error) {
+	c.runTask(job, fnc, job.Attempt == 0)
+}
+
+func (c *Cronger) runTask(job Job, fnc func(ctx context.Context) error, counted bool) {
+	ctx, cancel, ok := c.startRun(job)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	run := Run{
+		Tag:       job.Tag,
+		StartedAt: time.Now(),
+		Status:    Done,
+		Attempt:   job.Attempt,
+		Node:      c.node,
+	}
+
+	c.hooks.start(job, run)
+
+	err := fnc(ctx)
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("%w: %s", ErrJobTimeout, job.Timeout)
+	case ctx.Err() != nil:
+		// The run was interrupted by Remove, SetStatusCancelled, Shutdown or
+		// the parent context, the status is already consistent.
+		run.Status = Cancelled
+		if errors.Is(context.Cause(ctx), ErrShutdown) {
+			run.Status = Suspended
+		}
+		run.Error = context.Cause(ctx).Error()
+		run = c.finishRun(job, run)
+		if errors.Is(context.Cause(ctx), ErrJobReplaced) && counted {
+			// The occurrence is used, the next one finishes the job.
+			c.recordCount(job)
+		}
+		c.hooks.cancelled(job, run)
+		c.logger.Info("job run interrupted", jobAttrs(job, _error, run.Error)...)
+		return
+	}
+
+	if err != nil {
+		run.Status = Failed
+		run.Error = err.Error()
+	}
+	run = c.finishRun(job, run)
+	if err != nil {
+		c.hooks.failure(job, run, err)
+	} else {
+		c.hooks.success(job, run)
+	}
+
+	value := map[string]interface{}{
+		_statusDescription: "",
+		_attempt:           0,
+		_nextAttemptAt:     nil,
+	}
+	if counted {
+		value[_fireCount] = c.countFire(job.Tag)
+	}
+	value[_status] = c.statusAfterRun(job).String()
+
+	prev := job
+	var retryAt time.Time
+	if err != nil {
+		if value[_status] == Done.String() {
+			value[_status] = Failed.String()
 		}
+		value[_statusDescription] = err.Error()
+		if job.Attempt < job.MaxAttempts {
+			job.Attempt++
+			retryAt = time.Now().Add(job.RetryDelay(job.Attempt))
+			job.NextAttemptAt = &retryAt
+			value[_status] = Retrying.String()
+			value[_attempt] = job.Attempt
+			value[_nextAttemptAt] = retryAt
+		}
+	}
+
+	if err != nil {
+		c.logger.Warn("job run failed", jobAttrs(job, _status, value[_status], _attempt, job.Attempt, _error, err)...)
+	}
+
+	if err := c.update(job.Tag, value); err != nil {
+		c.logger.Error("update job status", jobAttrs(job, _status, value[_status], _error, err)...)
+	} else {
+		c.changeStatus(prev, Status(value[_status].(string)))
+	}
+
+	if !retryAt.IsZero() {
+		c.scheduleRetry(job, retryAt, fnc)
 		return
 	}
+	c.upstreamFinished(job, run.Status)
+}
+
+// statusAfterRun returns the status of the job after a run, it keeps working
+// while runs remain so that a restart restores it.
+func (c *Cronger) statusAfterRun(job Job) Status {
+	if c.limitUsed(job) {
+		return Done
+	}
+	return Working
+}
+
+// remaining returns the runs left of the limit.
+func (j Job) remaining() uint {
+	if j.FireCount >= j.Limit {
+		return 0
+	}
+	return j.Limit - j.FireCount
+}
+
+// countFire counts the run against the limit of the job and returns the runs
+// counted so far.
+func (c *Cronger) countFire(tag string) uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fired[tag]++
+	return c.fired[tag]
+}
+
+// limitUsed reports whether the counted runs used the limit of the job.
+func (c *Cronger) limitUsed(job Job) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return job.Limit != Unlimited && c.fired[job.Tag] >= job.Limit
+}
+
+func (c *Cronger) finishRun(job Job, run Run) Run {
+	run.FinishedAt = time.Now()
+	run.Duration = run.FinishedAt.Sub(run.StartedAt)
+	if err := c.addRun(run); err != nil {
+		c.logger.Error("add run", jobAttrs(job, _status, run.Status, _error, err)...)
+	}
+	return run
+}
+
+// changeStatus records the status of the scheduled job and reports the change.
+func (c *Cronger) changeStatus(job Job, status Status) {
+	c.mu.Lock()
+	if scheduled, ok := c.scheduled[job.Tag]; ok {
+		job.Status = scheduled.Status
+		scheduled.Status = status
+		c.scheduled[job.Tag] = scheduled
+	}
+	c.mu.Unlock()
+
+	c.hooks.statusChange(job, status)
+}
+
+func (c *Cronger) deleteScheduled(tag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.scheduled, tag)
+	delete(c.gates, tag)
+	delete(c.fired, tag)
+	c.removeDownstream(tag)
+	delete(c.downstream, tag)
+}
+
+// startRun registers the run, false when the scheduler is shut down.
+func (c *Cronger) startRun(job Job) (context.Context, context.CancelFunc, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, nil, false
+	}
+	c.inflight.Add(1)
+
+	parent, cancelCause := context.WithCancelCause(c.ctx)
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if job.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, job.Timeout)
+	}
 
-	job.Status = Done
-	job.StatusDescription = ""
-	if err := c.Update(job); err != nil {
-		log.Printf("set done: %v\n", err)
+	c.runID++
+	id := c.runID
+	if c.running[job.Tag] == nil {
+		c.running[job.Tag] = make(map[uint64]context.CancelCauseFunc)
+	}
+	c.running[job.Tag][id] = cancelCause
+
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.running[job.Tag], id)
+		if len(c.running[job.Tag]) == 0 {
+			delete(c.running, job.Tag)
+		}
+		c.mu.Unlock()
+
+		cancel()
+		cancelCause(context.Canceled)
+		c.inflight.Done()
+	}, true
+}
+
+func (c *Cronger) cancelRuns(tag string, cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.running[tag] {
+		cancel(cause)
 	}
 }
 
@@ -353,7 +988,7 @@ func structToMap(s interface{}) map[string]interface{} {
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
 		tag := typ.Field(i).Tag.Get("db")
-		if !field.IsZero() {
+		if tag != "-" && !field.IsZero() {
 			result[tag] = field.Interface()
 		}
 	}
@@ -0,0 +1,63 @@
+package cronger
+
+import (
+	"context"
+	"fmt"
+)
+
+// Definition binds a function name to a handler with a typed payload.
+type Definition[T any] struct {
+	name    string
+	handler func(ctx context.Context, job Job, payload T) error
+}
+
+func Define[T any](registry *Registry, name string, handler func(ctx context.Context, job Job, payload T) error) (*Definition[T], error) {
+	d := &Definition[T]{
+		name:    name,
+		handler: handler,
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("define %q: %w", name, ErrInvalidHandler)
+	}
+
+	if err := registry.Register(name, d.handle); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Definition[T]) Name() string {
+	return d.name
+}
+
+func (d *Definition[T]) Job(job Job, payload T) Job {
+	job.FunctionName = d.name
+	job.FunctionFields = FunctionFields{payload}
+	return job
+}
+
+func (d *Definition[T]) Add(c *Cronger, job Job, payload T) error {
+	return c.AddJob(d.Job(job, payload))
+}
+
+func (d *Definition[T]) handle(ctx context.Context, job Job, fields FunctionFields) error {
+	payload, err := Payload[T](fields)
+	if err != nil {
+		return err
+	}
+	return d.handler(ctx, job, payload)
+}
+
+func Payload[T any](fields FunctionFields) (T, error) {
+	if len(fields) > 0 {
+		if payload, ok := fields[0].(T); ok {
+			return payload, nil
+		}
+	}
+
+	var payload T
+	if err := fields.Decode(0, &payload); err != nil {
+		return payload, fmt.Errorf("payload: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,79 @@
+package cronger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+type testPayload struct {
+	Name  string
+	Age   int
+	ID    int64
+	Items []string
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields cronger.FunctionFields
+		want   testPayload
+	}{
+		{
+			name:   "struct",
+			fields: cronger.FunctionFields{testPayload{Name: "Nick", Age: 12, Items: []string{"a"}}},
+			want:   testPayload{Name: "Nick", Age: 12, Items: []string{"a"}},
+		},
+		{
+			name:   "large integer",
+			fields: cronger.FunctionFields{testPayload{ID: 1<<62 + 1}},
+			want:   testPayload{ID: 1<<62 + 1},
+		},
+		{
+			name:   "map",
+			fields: cronger.FunctionFields{map[string]interface{}{"Name": "Nick", "Age": 12}},
+			want:   testPayload{Name: "Nick", Age: 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.fields.Value()
+			require.NoError(t, err)
+
+			var scanned cronger.FunctionFields
+			require.NoError(t, scanned.Scan(value))
+			require.Len(t, scanned, 1)
+			assert.IsType(t, json.RawMessage{}, scanned[0])
+
+			payload, err := cronger.Payload[testPayload](scanned)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, payload)
+
+			// Values that were never stored are decoded as well.
+			payload, err = cronger.Payload[testPayload](tt.fields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, payload)
+		})
+	}
+}
+
+func TestFunctionFieldsDecode(t *testing.T) {
+	var fields cronger.FunctionFields
+	require.NoError(t, fields.Scan([]byte(`["name", 3]`)))
+
+	var name string
+	require.NoError(t, fields.Decode(0, &name))
+	assert.Equal(t, "name", name)
+
+	var count int
+	require.NoError(t, fields.Decode(1, &count))
+	assert.Equal(t, 3, count)
+
+	assert.ErrorIs(t, fields.Decode(2, &count), cronger.ErrFieldNotFound)
+	assert.Error(t, fields.Scan("text"))
+}
@@ -0,0 +1,222 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrDependencyCycle    = errors.New("job dependency cycle")
+	ErrUpstreamFailed     = errors.New("upstream job failed")
+	ErrDependenciesUpdate = errors.New("dependencies of a job cannot be updated")
+)
+
+// Dependency makes the job with the tag run after the upstream job.
+type Dependency struct {
+	Tag      string `db:"tag"`
+	Upstream string `db:"upstream"`
+}
+
+type DependencyPolicy string
+
+const (
+	// The run is skipped when an upstream job fails.
+	DependencySkip DependencyPolicy = "skip"
+	// The job is marked as failed when an upstream job fails.
+	DependencyFail DependencyPolicy = "fail"
+	// The job runs when the upstream jobs finish with any status.
+	DependencyRun DependencyPolicy = "run"
+)
+
+func (p DependencyPolicy) String() string {
+	return string(p)
+}
+
+// resolveDependencies loads the stored dependencies of a restored job without
+// a schedule and checks the new ones.
+func (c *Cronger) resolveDependencies(job *Job) error {
+	restored := job.RunAt == nil && len(job.Expression) == 0 &&
+		job.Interval == 0 && len(job.IntervalName) == 0
+	if len(job.DependsOn) == 0 && !restored {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	edges, err := c.cfg.Repository.Dependencies(ctx)
+	if err != nil {
+		return fmt.Errorf("dependencies: %w", err)
+	}
+
+	if len(job.DependsOn) == 0 {
+		for _, edge := range edges {
+			if edge.Tag == job.Tag {
+				job.DependsOn = append(job.DependsOn, edge.Upstream)
+			}
+		}
+		return nil
+	}
+
+	jobs, err := c.cfg.Repository.Jobs(ctx)
+	if err != nil {
+		return fmt.Errorf("dependencies: %w", err)
+	}
+	known := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		known[j.Tag] = true
+	}
+
+	upstream := make([]string, 0, len(job.DependsOn))
+	seen := make(map[string]bool, len(job.DependsOn))
+	for _, up := range job.DependsOn {
+		if seen[up] {
+			continue
+		}
+		seen[up] = true
+		if !known[up] && up != job.Tag {
+			return fmt.Errorf("upstream job %s: %w", up, ErrJobNotFound)
+		}
+		upstream = append(upstream, up)
+	}
+	job.DependsOn = upstream
+
+	if dependencyCycle(job.Tag, job.DependsOn, edges) {
+		return fmt.Errorf("job %s: %w", job.Tag, ErrDependencyCycle)
+	}
+	return nil
+}
+
+// dependencyCycle reports whether the job is its own upstream through the edges.
+func dependencyCycle(tag string, upstream []string, edges []Dependency) bool {
+	graph := map[string][]string{tag: upstream}
+	for _, edge := range edges {
+		if edge.Tag != tag {
+			graph[edge.Tag] = append(graph[edge.Tag], edge.Upstream)
+		}
+	}
+
+	visited := make(map[string]bool)
+	var visit func(string) bool
+	visit = func(t string) bool {
+		for _, up := range graph[t] {
+			if up == tag {
+				return true
+			}
+			if !visited[up] {
+				visited[up] = true
+				if visit(up) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return visit(tag)
+}
+
+func (c *Cronger) setDependencies(job Job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	if err := c.cfg.Repository.SetDependencies(ctx, job.Tag, job.DependsOn); err != nil {
+		return fmt.Errorf("set dependencies: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeDownstream(job.Tag)
+	for _, up := range job.DependsOn {
+		c.downstream[up] = append(c.downstream[up], job.Tag)
+	}
+	return nil
+}
+
+// removeDownstream forgets the job as a downstream one, c.mu is held.
+func (c *Cronger) removeDownstream(tag string) {
+	for up, tags := range c.downstream {
+		kept := tags[:0]
+		for _, t := range tags {
+			if t != tag {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.downstream, up)
+			continue
+		}
+		c.downstream[up] = kept
+	}
+	delete(c.rounds, tag)
+}
+
+// upstreamFinished triggers the downstream jobs whose upstream jobs have all
+// finished since their last run.
+func (c *Cronger) upstreamFinished(job Job, status Status) {
+	type trigger struct {
+		fields Fields
+		failed bool
+	}
+
+	c.mu.Lock()
+	var triggers []trigger
+	for _, tag := range c.downstream[job.Tag] {
+		fields, ok := c.scheduled[tag]
+		// A job that has used its limit is not run again.
+		if !ok || fields.Status == Paused || fields.Status == Done ||
+			(fields.Limit != Unlimited && c.fired[tag] >= fields.Limit) {
+			continue
+		}
+
+		round, ok := c.rounds[tag]
+		if !ok {
+			round = make(map[string]Status)
+			c.rounds[tag] = round
+		}
+		round[job.Tag] = status
+		if len(round) < len(fields.DependsOn) {
+			continue
+		}
+
+		failed := false
+		for _, s := range round {
+			failed = failed || s != Done
+		}
+		delete(c.rounds, tag)
+		triggers = append(triggers, trigger{fields: fields, failed: failed})
+	}
+	c.mu.Unlock()
+
+	for _, t := range triggers {
+		go c.trigger(t.fields, t.failed)
+	}
+}
+
+func (c *Cronger) trigger(fields Fields, failed bool) {
+	job := fields.Job
+	job.Attempt = 0
+	job.NextAttemptAt = nil
+	now := time.Now()
+
+	switch {
+	case !failed || job.OnUpstreamFailure == DependencyRun:
+		c.fire(job, fields.Task, now, true)
+	case job.OnUpstreamFailure == DependencyFail:
+		c.notRun(job, now, Failed, ErrUpstreamFailed)
+		if err := c.update(job.Tag, map[string]interface{}{
+			_status:            Failed.String(),
+			_statusDescription: ErrUpstreamFailed.Error(),
+			_fireCount:         c.countFire(job.Tag),
+		}); err != nil {
+			c.logger.Error("update job status", jobAttrs(job, _status, Failed, _error, err)...)
+		} else {
+			c.changeStatus(job, Failed)
+		}
+		c.upstreamFinished(job, Failed)
+	default:
+		c.skip(job, now, ErrUpstreamFailed, true)
+		c.upstreamFinished(job, Skipped)
+	}
+}
@@ -0,0 +1,323 @@
+package cronger_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func newDependencyJob(upstream ...string) cronger.Job {
+	return cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		DependsOn:      upstream,
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+	}
+}
+
+func TestDependencyPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   cronger.DependencyPolicy
+		fail     bool
+		runs     int32
+		status   cronger.Status
+		runState cronger.Status
+	}{
+		{name: "upstream done", runs: 1, status: cronger.Done, runState: cronger.Done},
+		// The skipped run uses the limit of the job.
+		{name: "skip", fail: true, status: cronger.Done, runState: cronger.Skipped},
+		{name: "fail", policy: cronger.DependencyFail, fail: true, status: cronger.Failed, runState: cronger.Failed},
+		{name: "run", policy: cronger.DependencyRun, fail: true, runs: 1, status: cronger.Done, runState: cronger.Done},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+			var upstreamRuns atomic.Int32
+			first := newDependencyJob()
+			second := newDependencyJob()
+			for _, job := range []cronger.Job{first, second} {
+				job.DependsOn = nil
+				at := time.Now().Add(50 * time.Millisecond)
+				job.RunAt = &at
+				require.NoError(t, cr.Add(cronger.Fields{
+					Job: job,
+					Task: func(ctx context.Context) error {
+						if upstreamRuns.Add(1) == 2 && tt.fail {
+							return errors.New("upstream failed")
+						}
+						return nil
+					},
+				}))
+			}
+
+			var runs atomic.Int32
+			downstream := newDependencyJob(first.Tag, second.Tag)
+			downstream.OnUpstreamFailure = tt.policy
+			require.NoError(t, cr.Add(cronger.Fields{
+				Job: downstream,
+				Task: func(ctx context.Context) error {
+					runs.Add(1)
+					return nil
+				},
+			}))
+
+			ctx := context.Background()
+			require.Eventually(t, func() bool {
+				history, err := repo.Runs(ctx, downstream.Tag, cronger.RunFilter{Status: tt.runState})
+				return err == nil && len(history) == 1
+			}, time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, tt.runs, runs.Load())
+			jobs, err := repo.JobsByStatus(ctx, tt.status)
+			require.NoError(t, err)
+			var tags []string
+			for _, job := range jobs {
+				tags = append(tags, job.Tag)
+			}
+			assert.Contains(t, tags, downstream.Tag)
+		})
+	}
+}
+
+func TestDependencyAdd(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	task := func(ctx context.Context) error { return nil }
+
+	upstream := newDependencyJob()
+	upstream.DependsOn = nil
+	upstream.Expression = "0 0 1 1 *"
+	require.NoError(t, cr.Add(cronger.Fields{Job: upstream, Task: task}))
+
+	middle := newDependencyJob(upstream.Tag)
+	require.NoError(t, cr.Add(cronger.Fields{Job: middle, Task: task}))
+	downstream := newDependencyJob(middle.Tag, middle.Tag)
+	require.NoError(t, cr.Add(cronger.Fields{Job: downstream, Task: task}))
+
+	dependencies, err := repo.Dependencies(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []cronger.Dependency{
+		{Tag: middle.Tag, Upstream: upstream.Tag},
+		{Tag: downstream.Tag, Upstream: middle.Tag},
+	}, dependencies)
+
+	tests := []struct {
+		name string
+		job  cronger.Job
+		err  error
+	}{
+		{name: "cycle", job: func() cronger.Job {
+			job := middle
+			job.DependsOn = []string{downstream.Tag}
+			return job
+		}(), err: cronger.ErrDependencyCycle},
+		{name: "self", job: func() cronger.Job {
+			job := middle
+			job.DependsOn = []string{middle.Tag}
+			return job
+		}(), err: cronger.ErrDependencyCycle},
+		{name: "unknown upstream", job: newDependencyJob(uuid.NewString()), err: cronger.ErrJobNotFound},
+		{name: "with schedule", job: func() cronger.Job {
+			job := newDependencyJob(upstream.Tag)
+			job.Expression = "* * * * *"
+			return job
+		}(), err: cronger.ErrInvalidSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cr.Add(cronger.Fields{Job: tt.job, Task: task})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// A restored job without a schedule keeps its stored upstream jobs.
+	restored := downstream
+	restored.DependsOn = nil
+	require.NoError(t, cr.Add(cronger.Fields{Job: restored, Task: task}))
+	dependencies, err = repo.Dependencies(ctx)
+	require.NoError(t, err)
+	assert.Len(t, dependencies, 2)
+}
+
+func TestDependencyCancel(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(cr *cronger.Cronger, job, upstream cronger.Job) error
+		reason error
+	}{
+		{
+			name: "remove",
+			cancel: func(cr *cronger.Cronger, job, _ cronger.Job) error {
+				return cr.Remove(job.Tag)
+			},
+			reason: cronger.ErrJobRemoved,
+		},
+		{
+			name: "set status cancelled",
+			cancel: func(cr *cronger.Cronger, job, upstream cronger.Job) error {
+				return cr.SetStatusCancelled([]string{job.ID, upstream.ID}, job.FunctionName)
+			},
+			reason: cronger.ErrJobCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: cronger.NewMemory(),
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				// Runs left behind by a failed step are cancelled.
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_ = cr.Shutdown(ctx)
+			})
+
+			task := func(ctx context.Context) error { return nil }
+			upstream := newDependencyJob()
+			upstream.Expression = "0 0 1 1 *"
+			require.NoError(t, cr.Add(cronger.Fields{Job: upstream, Task: task}))
+			// The downstream job has no gocron entry.
+			job := newDependencyJob(upstream.Tag)
+			require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: task}))
+
+			started := make(chan struct{})
+			done := make(chan error, 1)
+			go cr.Template(job, func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				done <- context.Cause(ctx)
+				return ctx.Err()
+			})
+			<-started
+
+			require.NoError(t, tt.cancel(cr, job, upstream))
+			select {
+			case cause := <-done:
+				assert.ErrorIs(t, cause, tt.reason)
+			case <-time.After(time.Second):
+				t.Fatal("task is not cancelled")
+			}
+			assert.ErrorIs(t, cr.Trigger(job.Tag), cronger.ErrJobNotFound)
+		})
+	}
+}
+
+func TestDependencyRemove(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	task := func(ctx context.Context) error { return nil }
+	upstream := newDependencyJob()
+	upstream.DependsOn = nil
+	upstream.Expression = "0 0 1 1 *"
+	upstream.Limit = 10
+	require.NoError(t, cr.Add(cronger.Fields{Job: upstream, Task: task}))
+
+	var runs atomic.Int32
+	downstream := newDependencyJob(upstream.Tag)
+	downstream.Limit = 10
+	require.NoError(t, cr.Add(cronger.Fields{
+		Job: downstream,
+		Task: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}))
+
+	cr.Template(upstream, task)
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The downstream job has no gocron entry.
+	require.NoError(t, cr.Remove(downstream.Tag))
+	assert.ErrorIs(t, cr.Trigger(downstream.Tag), cronger.ErrJobNotFound)
+	dependencies, err := repo.Dependencies(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+
+	cr.Template(upstream, task)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load())
+	history, err := repo.Runs(ctx, upstream.Tag, cronger.RunFilter{})
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestDependencyLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	task := func(ctx context.Context) error { return nil }
+	upstream := newDependencyJob()
+	upstream.DependsOn = nil
+	upstream.Interval = 20 * time.Millisecond
+	upstream.Limit = 10
+	require.NoError(t, cr.Add(cronger.Fields{Job: upstream, Task: task}))
+
+	var runs atomic.Int32
+	downstream := newDependencyJob(upstream.Tag)
+	downstream.Limit = 2
+	require.NoError(t, cr.Add(cronger.Fields{
+		Job: downstream,
+		Task: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	}))
+
+	require.Eventually(t, func() bool {
+		history, err := repo.Runs(ctx, upstream.Tag, cronger.RunFilter{})
+		return err == nil && len(history) == int(upstream.Limit)
+	}, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, int32(downstream.Limit), runs.Load())
+	jobs, err := repo.JobsByStatus(ctx, cronger.Done)
+	require.NoError(t, err)
+	var tags []string
+	for _, job := range jobs {
+		tags = append(tags, job.Tag)
+	}
+	assert.Contains(t, tags, downstream.Tag)
+}
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"reflect"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,111 +17,71 @@ const (
 	dns = "postgres://postgres@localhost:5432/postgres?sslmode=disable"
 )
 
-type User struct {
-	cr   *cronger.Cronger
-	Name string
-}
-
 type Type struct {
 	Age    int
 	Number int
 }
 
-func (u *User) Test(job cronger.Job, t Type) {
-	if err := u.cr.Add(
-		cronger.Fields{
-			Job: job,
-			Task: func() error {
-				return Test("test", t)
-			},
-		}); err != nil {
-		log.Fatalln(err)
-	}
+type Payload struct {
+	Name string
+	Type Type
 }
 
-func Test(name string, t Type) error {
-	fmt.Printf("Running %s\n", name)
-	fmt.Printf("Type: %v", t)
+func Test(_ context.Context, _ cronger.Job, p Payload) error {
+	fmt.Printf("Running %s\n", p.Name)
+	fmt.Printf("Type: %v", p.Type)
 	return nil
 }
 
-func Invoke(any interface{}, name string, job cronger.Job) {
-	inputs := make([]reflect.Value, len(job.FunctionFields)+1)
-	inputs[0] = reflect.ValueOf(job)
-	for i := 1; i < len(inputs); i++ {
-		if i == len(inputs)-1 {
-			m := job.FunctionFields[i-1].(map[string]interface{})
-			t := Type{
-				Age:    int(m["Age"].(float64)),
-				Number: int(m["Number"].(float64)),
-			}
-			inputs[i] = reflect.ValueOf(t)
-			break
-		}
-		inputs[i] = reflect.ValueOf(job.FunctionFields[i-1])
-	}
-	reflect.ValueOf(any).MethodByName(name).Call(inputs)
-}
-
 func main() {
 	db, err := sqlx.Connect("postgres", dns)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	if err := cronger.Migrate(context.Background(), db); err != nil {
+		log.Fatalln(err)
+	}
+
+	registry := cronger.NewRegistry()
+	test, err := cronger.Define(registry, "Test", Test)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Suspended jobs are restored with the registered handlers
 	cr, err := cronger.New(
 		&cronger.Config{
 			Loc:        time.UTC,
 			Repository: cronger.NewSqlx(db),
+			Registry:   registry,
 		})
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	u := User{
-		Name: "Nick",
-		cr:   cr,
-	}
-
-	// Recover Task
-	jobs := cr.SuspendJobs()
-	fmt.Println(jobs)
-	if len(jobs) > 0 {
-		for _, job := range jobs {
-			Invoke(&u, job.FunctionName, job)
-		}
-	}
-
-	t := Type{
-		Age:    12,
-		Number: 11,
-	}
 	tag1 := uuid.NewString()
 	id := uuid.NewString()
-	data := []interface{}{
-		u.Name,
-		t,
+	payload := Payload{
+		Name: "Nick",
+		Type: Type{
+			Age:    12,
+			Number: 11,
+		},
 	}
 
 	job := cronger.Job{
-		Tag:            tag1,
-		ID:             id,
-		Limit:          1,
-		Expression:     "* * * * *",
-		FunctionName:   "Test",
-		FunctionFields: data,
+		Tag:        tag1,
+		ID:         id,
+		Limit:      1,
+		Expression: "* * * * *",
+		Timeout:    time.Second * 30,
 	}
-	if err := cr.Add(
-		cronger.Fields{
-			Job: job,
-			Task: func() error {
-				return Test(u.Name, t)
-			},
-		}); err != nil {
+	if err := test.Add(cr, job, payload); err != nil {
 		log.Fatalln(err)
 	}
 
-	jobs, err = cr.Jobs()
+	jobs, err := cr.Jobs()
 	if err != nil {
 		log.Fatalln(jobs)
 	}
@@ -0,0 +1,128 @@
+package cronger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrInvalidExpression = errors.New("invalid expression")
+
+type ExpressionFormat string
+
+const (
+	// Five fields starting with minutes.
+	FormatStandard ExpressionFormat = "standard"
+	// Six fields starting with seconds.
+	FormatSeconds ExpressionFormat = "seconds"
+	// Descriptors such as @daily or @every 90s.
+	FormatDescriptor ExpressionFormat = "descriptor"
+)
+
+func (f ExpressionFormat) String() string {
+	return string(f)
+}
+
+const _schedulerTimezone = "CRON_TZ=Local "
+
+var (
+	_standardParser   = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	_secondsParser    = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	_descriptorParser = cron.NewParser(cron.Descriptor)
+)
+
+// Format returns the format of the expression, detected by its fields when
+// ExpressionFormat is not set.
+func (j Job) Format() (ExpressionFormat, error) {
+	format := j.ExpressionFormat
+	if len(format) == 0 {
+		format = detectFormat(j.Expression)
+	}
+	if len(j.Location) != 0 && hasTimezone(j.Expression) {
+		return "", fmt.Errorf("expression %q with location %s: %w", j.Expression, j.Location, ErrInvalidExpression)
+	}
+	if _, err := parseExpression(j.spec(), format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// spec returns the expression evaluated in the job location.
+func (j Job) spec() string {
+	if len(j.Location) == 0 || hasTimezone(j.Expression) {
+		return j.Expression
+	}
+	return "CRON_TZ=" + j.Location + " " + strings.TrimSpace(j.Expression)
+}
+
+// schedulerSpec returns the expression for gocron, which otherwise names the
+// scheduler location in CRON_TZ. Local evaluates the expression in the location
+// of the scheduler times, so zones without a name such as time.FixedZone work.
+func (j Job) schedulerSpec() string {
+	if len(j.Location) != 0 || hasTimezone(j.Expression) {
+		return j.spec()
+	}
+	return _schedulerTimezone + strings.TrimSpace(j.Expression)
+}
+
+func hasTimezone(expression string) bool {
+	spec := strings.TrimSpace(expression)
+	return strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=")
+}
+
+func detectFormat(expression string) ExpressionFormat {
+	spec := strings.TrimSpace(expression)
+	if hasTimezone(spec) {
+		_, spec, _ = strings.Cut(spec, " ")
+	}
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(spec), "@"):
+		return FormatDescriptor
+	case len(strings.Fields(spec)) == 6:
+		return FormatSeconds
+	default:
+		return FormatStandard
+	}
+}
+
+// parseIn parses the job expression, a job without a location of its own is
+// evaluated in loc. The location is set on the schedule rather than by name,
+// so zones such as time.FixedZone work as well.
+func parseIn(job Job, loc *time.Location) (cron.Schedule, error) {
+	format, err := job.Format()
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := parseExpression(job.spec(), format)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok && len(job.Location) == 0 && !hasTimezone(job.Expression) {
+		spec.Location = loc
+	}
+	return schedule, nil
+}
+
+func parseExpression(expression string, format ExpressionFormat) (cron.Schedule, error) {
+	var parser cron.Parser
+	switch format {
+	case FormatStandard:
+		parser = _standardParser
+	case FormatSeconds:
+		parser = _secondsParser
+	case FormatDescriptor:
+		parser = _descriptorParser
+	default:
+		return nil, fmt.Errorf("format %q: %w", format, ErrInvalidExpression)
+	}
+
+	schedule, err := parser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%s expression %q: %w: %s", format, expression, ErrInvalidExpression, err)
+	}
+	return schedule, nil
+}
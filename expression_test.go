@@ -0,0 +1,83 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestJobFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		format     cronger.ExpressionFormat
+		location   string
+		want       cronger.ExpressionFormat
+		wantErr    bool
+	}{
+		{name: "standard", expression: "*/5 * * * *", want: cronger.FormatStandard},
+		{name: "seconds", expression: "*/10 * * * * *", want: cronger.FormatSeconds},
+		{name: "descriptor", expression: "@daily", want: cronger.FormatDescriptor},
+		{name: "every", expression: "@every 90s", want: cronger.FormatDescriptor},
+		{name: "timezone", expression: "CRON_TZ=Europe/Moscow 0 9 * * MON-FRI", want: cronger.FormatStandard},
+		{name: "explicit", expression: "0 0 9 * * *", format: cronger.FormatSeconds, want: cronger.FormatSeconds},
+		{name: "mismatch", expression: "0 9 * * *", format: cronger.FormatSeconds, wantErr: true},
+		{name: "unknown_descriptor", expression: "@sometimes", wantErr: true},
+		{name: "invalid", expression: "61 * * * *", wantErr: true},
+		{name: "unknown_format", expression: "* * * * *", format: "quartz", wantErr: true},
+		{name: "location", expression: "0 9 * * *", location: "America/New_York", want: cronger.FormatStandard},
+		{name: "location_and_timezone", expression: "CRON_TZ=UTC 0 9 * * *", location: "America/New_York", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cronger.Job{
+				Expression:       tt.expression,
+				ExpressionFormat: tt.format,
+				Location:         tt.location,
+			}.Format()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, cronger.ErrInvalidExpression)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJobLocation(t *testing.T) {
+	job := cronger.Job{Tag: uuid.NewString(), Location: "Mars/Olympus"}
+	assert.Error(t, job.CheckUpdate())
+
+	job.Location = "Europe/Berlin"
+	assert.NoError(t, job.CheckUpdate())
+}
+
+func TestJobFixedZone(t *testing.T) {
+	// Config.Loc may be a zone without a name.
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.FixedZone("UTC+03:00", 3*3600),
+		Repository: cronger.NewMemory(),
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	for _, expression := range []string{"0 9 * * *", "0 0 9 * * *"} {
+		job := cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			Expression:     expression,
+			FunctionName:   "test",
+			FunctionFields: cronger.FunctionFields{},
+			Limit:          1,
+		}
+		assert.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+	}
+}
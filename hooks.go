@@ -0,0 +1,157 @@
+package cronger
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	_hooksBufferSize = 1024
+)
+
+// Hooks are called with the job events in order of occurrence from a separate
+// goroutine, a slow hook delays the following hooks but never the scheduler.
+type Hooks struct {
+	// Job is added to the schedule.
+	OnScheduled func(job Job)
+	OnStart     func(job Job, run Run)
+	OnSuccess   func(job Job, run Run)
+	OnFailure   func(job Job, run Run, err error)
+	// Run is interrupted by Remove, SetStatusCancelled or the parent context.
+	OnCancelled func(job Job, run Run)
+	// Stored status of the job is changed, job holds the previous status.
+	OnStatusChange func(job Job, status Status)
+	// Size of the event queue, events are dropped when it is full. 1024 by default.
+	BufferSize int
+}
+
+type hooks struct {
+	Hooks
+	events chan hookEvent
+	done   chan struct{}
+	logger Logger
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type hookEvent struct {
+	name string
+	job  Job
+	call func()
+}
+
+// newHooks starts the goroutine calling the hooks, nil when no hook is set.
+func newHooks(h *Hooks, logger Logger) *hooks {
+	if h == nil || (h.OnScheduled == nil && h.OnStart == nil && h.OnSuccess == nil &&
+		h.OnFailure == nil && h.OnCancelled == nil && h.OnStatusChange == nil) {
+		return nil
+	}
+
+	size := h.BufferSize
+	if size <= 0 {
+		size = _hooksBufferSize
+	}
+
+	hs := &hooks{
+		Hooks:  *h,
+		events: make(chan hookEvent, size),
+		done:   make(chan struct{}),
+		logger: logger,
+	}
+	go hs.run()
+	return hs
+}
+
+func (h *hooks) run() {
+	defer close(h.done)
+	for event := range h.events {
+		h.call(event)
+	}
+}
+
+// close drops the following events and waits for the queued ones until ctx is done.
+func (h *hooks) close(ctx context.Context) error {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.events)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *hooks) call(event hookEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error("hook panic", jobAttrs(event.job, "hook", event.name, _error, r)...)
+		}
+	}()
+	event.call()
+}
+
+func (h *hooks) emit(name string, job Job, call func()) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		return
+	}
+
+	select {
+	case h.events <- hookEvent{name: name, job: job, call: call}:
+	default:
+		h.logger.Warn("hook queue is full, event dropped", jobAttrs(job, "hook", name)...)
+	}
+}
+
+func (h *hooks) scheduled(job Job) {
+	if h == nil || h.OnScheduled == nil {
+		return
+	}
+	h.emit("OnScheduled", job, func() { h.OnScheduled(job) })
+}
+
+func (h *hooks) start(job Job, run Run) {
+	if h == nil || h.OnStart == nil {
+		return
+	}
+	h.emit("OnStart", job, func() { h.OnStart(job, run) })
+}
+
+func (h *hooks) success(job Job, run Run) {
+	if h == nil || h.OnSuccess == nil {
+		return
+	}
+	h.emit("OnSuccess", job, func() { h.OnSuccess(job, run) })
+}
+
+func (h *hooks) failure(job Job, run Run, err error) {
+	if h == nil || h.OnFailure == nil {
+		return
+	}
+	h.emit("OnFailure", job, func() { h.OnFailure(job, run, err) })
+}
+
+func (h *hooks) cancelled(job Job, run Run) {
+	if h == nil || h.OnCancelled == nil {
+		return
+	}
+	h.emit("OnCancelled", job, func() { h.OnCancelled(job, run) })
+}
+
+func (h *hooks) statusChange(job Job, status Status) {
+	if h == nil || h.OnStatusChange == nil || job.Status == status {
+		return
+	}
+	h.emit("OnStatusChange", job, func() { h.OnStatusChange(job, status) })
+}
@@ -0,0 +1,75 @@
+package cronger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestHooksDoNotBlockScheduler(t *testing.T) {
+	events := make(chan string, 10)
+	release := make(chan struct{})
+	defer close(release)
+
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: cronger.NewMemory(),
+		Hooks: &cronger.Hooks{
+			OnStart: func(cronger.Job, cronger.Run) {
+				<-release
+				events <- "start"
+			},
+			OnFailure: func(_ cronger.Job, run cronger.Run, err error) {
+				events <- "failure: " + err.Error()
+			},
+			OnStatusChange: func(job cronger.Job, status cronger.Status) {
+				events <- string(job.Status) + " -> " + string(status)
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "fail",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{
+		Job:  job,
+		Task: func(context.Context) error { return nil },
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		cr.Template(job, func(context.Context) error { return errors.New("boom") })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run is blocked by a hook")
+	}
+
+	release <- struct{}{}
+	var got []string
+	for i := 0; i < 4; i++ {
+		select {
+		case event := <-events:
+			got = append(got, event)
+		case <-time.After(time.Second):
+			t.Fatalf("missing events, got %v", got)
+		}
+	}
+	assert.Equal(t, []string{" -> working", "start", "failure: boom", "working -> failed"}, got)
+}
@@ -0,0 +1,62 @@
+package cronger_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestIntervalJob(t *testing.T) {
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:          time.UTC,
+		Repository:   repo,
+		JobIntervals: map[string]time.Duration{"fast": 50 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	var runs atomic.Int32
+	task := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+	newJob := func(name string) cronger.Job {
+		return cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			IntervalName:   name,
+			FunctionName:   "interval",
+			FunctionFields: cronger.FunctionFields{},
+			Limit:          2,
+		}
+	}
+
+	err = cr.Add(cronger.Fields{Job: newJob("slow"), Task: task})
+	assert.ErrorIs(t, err, cronger.ErrJobIntervalNotFound)
+
+	both := newJob("fast")
+	both.Expression = "* * * * *"
+	err = cr.Add(cronger.Fields{Job: both, Task: task})
+	assert.ErrorIs(t, err, cronger.ErrInvalidSchedule)
+
+	job := newJob("fast")
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: task}))
+	assert.Eventually(t, func() bool { return runs.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(2), runs.Load())
+
+	jobs, err := repo.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "fast", jobs[0].IntervalName)
+	assert.Equal(t, cronger.Done, jobs[0].Status)
+}
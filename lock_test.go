@@ -0,0 +1,109 @@
+package cronger_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+	"github.com/vladjong/cronger/mocks"
+)
+
+func TestDistributedLock(t *testing.T) {
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+	}
+
+	// The occurrence is claimed by the first instance only.
+	var claims atomic.Int32
+	repo := &mocks.Repository{}
+	repo.On("SuspendJobs", mock.Anything).Return([]cronger.Job{}, nil)
+	// Jobs are polled every minute for the done status.
+	repo.On("Jobs", mock.Anything).Return([]cronger.Job{}, nil).Maybe()
+	repo.On("Add", mock.Anything, mock.Anything).Return(nil)
+	repo.On("Update", mock.Anything, job.Tag, mock.Anything).Return(nil)
+	repo.On("AddRun", mock.Anything, mock.Anything).Return(nil)
+	repo.On("TryLock", mock.Anything, job.Tag, mock.MatchedBy(func(at time.Time) bool {
+		return at.Equal(at.Truncate(time.Microsecond))
+	})).Run(func(mock.Arguments) { claims.Add(1) }).Return(true, nil).Once()
+	repo.On("TryLock", mock.Anything, job.Tag, mock.Anything).Run(func(mock.Arguments) { claims.Add(1) }).Return(false, nil)
+
+	var runs atomic.Int32
+	for i := 0; i < 2; i++ {
+		cr, err := cronger.New(&cronger.Config{
+			Loc:             time.UTC,
+			Repository:      repo,
+			DistributedLock: true,
+		})
+		require.NoError(t, err)
+		require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}}))
+		require.NoError(t, cr.Recover(job.Tag))
+	}
+
+	require.Eventually(t, func() bool {
+		return claims.Load() == 2 && runs.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+func TestMemoryRepositoryTryLock(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "* * * * *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+	}
+	require.NoError(t, repo.Add(ctx, job))
+
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		tag         string
+		scheduledAt time.Time
+		want        bool
+	}{
+		{name: "first claim", tag: job.Tag, scheduledAt: at, want: true},
+		{name: "same occurrence", tag: job.Tag, scheduledAt: at, want: false},
+		{name: "same occurrence in another zone", tag: job.Tag, scheduledAt: at.In(time.FixedZone("UTC+3", 3*3600)), want: false},
+		{name: "next occurrence", tag: job.Tag, scheduledAt: at.Add(time.Minute), want: true},
+		{name: "unknown job", tag: uuid.NewString(), scheduledAt: at, want: false},
+	}
+
+	// Steps run in order, each claim depends on the previous ones.
+	for _, tt := range tests {
+		ok, err := repo.TryLock(ctx, tt.tag, tt.scheduledAt)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, ok, tt.name)
+	}
+
+	// Upserting the job keeps the claimed occurrence.
+	require.NoError(t, repo.Add(ctx, job))
+	ok, err := repo.TryLock(ctx, job.Tag, at.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	jobs, err := repo.Jobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.NotNil(t, jobs[0].FiredAt)
+	assert.True(t, at.Add(time.Minute).Equal(*jobs[0].FiredAt))
+}
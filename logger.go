@@ -0,0 +1,69 @@
+package cronger
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives the errors of the scheduler and the repositories as a message
+// with key-value pairs, *slog.Logger implements it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+const (
+	_error       = "error"
+	_scheduledAt = "scheduled_at"
+)
+
+// stdLogger writes to the standard log package, debug messages are dropped.
+type stdLogger struct {
+	l *log.Logger
+}
+
+func defaultLogger() Logger {
+	return stdLogger{l: log.Default()}
+}
+
+func (s stdLogger) Debug(string, ...interface{}) {}
+
+func (s stdLogger) Info(msg string, args ...interface{}) {
+	s.print("INFO", msg, args)
+}
+
+func (s stdLogger) Warn(msg string, args ...interface{}) {
+	s.print("WARN", msg, args)
+}
+
+func (s stdLogger) Error(msg string, args ...interface{}) {
+	s.print("ERROR", msg, args)
+}
+
+func (s stdLogger) print(level, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&b, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+	}
+	s.l.Println(b.String())
+}
+
+// jobAttrs returns the fields identifying the job in the log.
+func jobAttrs(job Job, args ...interface{}) []interface{} {
+	attrs := []interface{}{
+		_tag, job.Tag,
+		_id, job.ID,
+		_functionName, job.FunctionName,
+	}
+	return append(attrs, args...)
+}
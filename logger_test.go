@@ -0,0 +1,11 @@
+//go:build go1.21
+
+package cronger_test
+
+import (
+	"log/slog"
+
+	"github.com/vladjong/cronger"
+)
+
+var _ cronger.Logger = (*slog.Logger)(nil)
@@ -0,0 +1,329 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDuplicateJob  = errors.New("job with the same id and function name already exists")
+	ErrUnknownColumn = errors.New("unknown column")
+	ErrUnknownJob    = errors.New("unknown job")
+)
+
+var _ Repository = (*MemoryRepository)(nil)
+
+// MemoryRepository keeps jobs in memory with the semantics of SqlxRepository.
+type MemoryRepository struct {
+	mu       sync.Mutex
+	jobs     map[string]Job
+	upstream map[string][]string
+	runs     []Run
+	runID    int64
+}
+
+func NewMemory() *MemoryRepository {
+	return &MemoryRepository{
+		jobs:     make(map[string]Job),
+		upstream: make(map[string][]string),
+	}
+}
+
+func (r *MemoryRepository) Jobs(_ context.Context) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.filter(func(Job) bool { return true }), nil
+}
+
+func (r *MemoryRepository) JobsByStatus(_ context.Context, status Status) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.filter(func(job Job) bool { return job.Status == status }), nil
+}
+
+func (r *MemoryRepository) Add(_ context.Context, in Job) error {
+	// Round-trip the fields as the database does.
+	value, err := in.FunctionFields.Value()
+	if err != nil {
+		return fmt.Errorf("insert job: %w", err)
+	}
+	var fields FunctionFields
+	if err := fields.Scan(value); err != nil {
+		return fmt.Errorf("insert job: %w", err)
+	}
+	in.FunctionFields = fields
+	// Dependencies are stored separately.
+	in.DependsOn = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for tag, job := range r.jobs {
+		if tag != in.Tag && job.ID == in.ID && job.FunctionName == in.FunctionName {
+			return fmt.Errorf("insert job: %w", ErrDuplicateJob)
+		}
+	}
+
+	// The claimed occurrence is kept as the column is skipped on upsert.
+	in.FiredAt = nil
+	if job, ok := r.jobs[in.Tag]; ok {
+		in.CreatedAt = job.CreatedAt
+		in.FiredAt = job.FiredAt
+	} else if in.CreatedAt.IsZero() {
+		in.CreatedAt = time.Now()
+	}
+	r.jobs[in.Tag] = in
+	return nil
+}
+
+func (r *MemoryRepository) SuspendJobs(_ context.Context) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for tag, job := range r.jobs {
+		if job.Status == Working || job.Status == Retrying {
+			job.Status = Suspended
+			r.jobs[tag] = job
+		}
+	}
+	return r.filter(func(job Job) bool { return job.Status == Suspended }), nil
+}
+
+func (r *MemoryRepository) SetStatusCancelled(_ context.Context, ids []string, functionName string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		in[id] = struct{}{}
+	}
+
+	jobs := r.filter(func(job Job) bool {
+		_, ok := in[job.ID]
+		return ok && job.Status == Working && job.FunctionName == functionName
+	})
+
+	tags := make([]string, len(jobs))
+	for i, job := range jobs {
+		job.Status = Cancelled
+		r.jobs[job.Tag] = job
+		tags[i] = job.Tag
+	}
+	return tags, nil
+}
+
+func (r *MemoryRepository) Remove(_ context.Context, tag string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.jobs, tag)
+	delete(r.upstream, tag)
+	for downstream, upstream := range r.upstream {
+		kept := make([]string, 0, len(upstream))
+		for _, up := range upstream {
+			if up != tag {
+				kept = append(kept, up)
+			}
+		}
+		if len(kept) == 0 {
+			delete(r.upstream, downstream)
+			continue
+		}
+		r.upstream[downstream] = kept
+	}
+	return nil
+}
+
+func (r *MemoryRepository) Update(_ context.Context, tag string, in map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[tag]
+	if !ok {
+		return nil
+	}
+
+	val := reflect.ValueOf(&job).Elem()
+	for column, value := range in {
+		if err := setColumn(val, column, value); err != nil {
+			return fmt.Errorf("update: %w", err)
+		}
+	}
+	r.jobs[tag] = job
+	return nil
+}
+
+func (r *MemoryRepository) UpdateStatus(_ context.Context, tag string, status Status) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job, ok := r.jobs[tag]; ok {
+		job.Status = status
+		r.jobs[tag] = job
+	}
+	return nil
+}
+
+func (r *MemoryRepository) AddRun(_ context.Context, in Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runID++
+	in.ID = r.runID
+	r.runs = append(r.runs, in)
+	return nil
+}
+
+func (r *MemoryRepository) Runs(_ context.Context, tag string, filter RunFilter) ([]Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []Run
+	for _, run := range r.runs {
+		if run.Tag != tag ||
+			(len(filter.Status) != 0 && run.Status != filter.Status) ||
+			(!filter.From.IsZero() && run.StartedAt.Before(filter.From)) ||
+			(!filter.To.IsZero() && !run.StartedAt.Before(filter.To)) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.After(runs[j].StartedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+
+	if uint(len(runs)) <= filter.Offset {
+		return nil, nil
+	}
+	runs = runs[filter.Offset:]
+	if filter.Limit != 0 && uint(len(runs)) > filter.Limit {
+		runs = runs[:filter.Limit]
+	}
+	return runs, nil
+}
+
+func (r *MemoryRepository) TryLock(_ context.Context, tag string, scheduledAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[tag]
+	if !ok {
+		return false, nil
+	}
+	if job.FiredAt != nil && job.FiredAt.Equal(scheduledAt) {
+		return false, nil
+	}
+	job.FiredAt = &scheduledAt
+	r.jobs[tag] = job
+	return true, nil
+}
+
+func (r *MemoryRepository) SetDependencies(_ context.Context, tag string, upstream []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Both jobs must exist as with the foreign keys of the table.
+	for _, t := range append([]string{tag}, upstream...) {
+		if _, ok := r.jobs[t]; !ok {
+			return fmt.Errorf("insert dependencies tag=%s: %w", t, ErrUnknownJob)
+		}
+	}
+
+	if len(upstream) == 0 {
+		delete(r.upstream, tag)
+		return nil
+	}
+	r.upstream[tag] = append([]string(nil), upstream...)
+	return nil
+}
+
+func (r *MemoryRepository) Dependencies(_ context.Context) ([]Dependency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var dependencies []Dependency
+	for tag, upstream := range r.upstream {
+		for _, up := range upstream {
+			dependencies = append(dependencies, Dependency{Tag: tag, Upstream: up})
+		}
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Tag != dependencies[j].Tag {
+			return dependencies[i].Tag < dependencies[j].Tag
+		}
+		return dependencies[i].Upstream < dependencies[j].Upstream
+	})
+	return dependencies, nil
+}
+
+func (r *MemoryRepository) filter(match func(Job) bool) []Job {
+	var jobs []Job
+	for _, job := range r.jobs {
+		if match(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].Tag < jobs[j].Tag
+	})
+	return jobs
+}
+
+// setColumn sets the struct field with the given db tag as goqu does for a record.
+func setColumn(val reflect.Value, column string, value interface{}) error {
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		if typ.Field(i).Tag.Get("db") != column {
+			continue
+		}
+
+		field := val.Field(i)
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+			v = v.Elem()
+		}
+
+		target := field.Type()
+		if target.Kind() == reflect.Pointer {
+			target = target.Elem()
+		}
+		if !v.Type().ConvertibleTo(target) || (target.Kind() == reflect.String && v.Kind() != reflect.String) {
+			return fmt.Errorf("column %s: cannot use %T", column, value)
+		}
+		v = v.Convert(target)
+
+		if field.Kind() == reflect.Pointer {
+			ptr := reflect.New(target)
+			ptr.Elem().Set(v)
+			v = ptr
+		}
+		field.Set(v)
+		return nil
+	}
+	return fmt.Errorf("column %s: %w", column, ErrUnknownColumn)
+}
@@ -0,0 +1,280 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func newMemoryJob(status cronger.Status) cronger.Job {
+	return cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "* * * * *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+		Status:         status,
+	}
+}
+
+func TestMemoryRepositoryAdd(t *testing.T) {
+	ctx := context.Background()
+	existing := newMemoryJob(cronger.Working)
+
+	tests := []struct {
+		name    string
+		job     func() cronger.Job
+		wantErr error
+		count   int
+	}{
+		{
+			name:  "new job",
+			job:   func() cronger.Job { return newMemoryJob(cronger.Working) },
+			count: 2,
+		},
+		{
+			name: "upsert on tag",
+			job: func() cronger.Job {
+				job := existing
+				job.Expression = "0 * * * *"
+				job.Status = cronger.Failed
+				return job
+			},
+			count: 1,
+		},
+		{
+			name: "same id and function name",
+			job: func() cronger.Job {
+				job := newMemoryJob(cronger.Working)
+				job.ID = existing.ID
+				return job
+			},
+			wantErr: cronger.ErrDuplicateJob,
+			count:   1,
+		},
+		{
+			name: "same id with another function",
+			job: func() cronger.Job {
+				job := newMemoryJob(cronger.Working)
+				job.ID = existing.ID
+				job.FunctionName = "other"
+				return job
+			},
+			count: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			require.NoError(t, repo.Add(ctx, existing))
+			before, err := repo.Jobs(ctx)
+			require.NoError(t, err)
+
+			job := tt.job()
+			err = repo.Add(ctx, job)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			jobs, err := repo.Jobs(ctx)
+			require.NoError(t, err)
+			require.Len(t, jobs, tt.count)
+			if tt.wantErr != nil {
+				return
+			}
+			for _, stored := range jobs {
+				if stored.Tag != job.Tag {
+					continue
+				}
+				assert.Equal(t, job.Expression, stored.Expression)
+				assert.Equal(t, job.Status, stored.Status)
+				if job.Tag == existing.Tag {
+					assert.Equal(t, before[0].CreatedAt, stored.CreatedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryRepositorySuspendJobs(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		status cronger.Status
+		want   cronger.Status
+	}{
+		{status: cronger.Working, want: cronger.Suspended},
+		{status: cronger.Retrying, want: cronger.Suspended},
+		{status: cronger.Suspended, want: cronger.Suspended},
+		{status: cronger.Done, want: cronger.Done},
+		{status: cronger.Failed, want: cronger.Failed},
+		{status: cronger.Cancelled, want: cronger.Cancelled},
+		{status: cronger.Paused, want: cronger.Paused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			repo := cronger.NewMemory()
+			job := newMemoryJob(tt.status)
+			require.NoError(t, repo.Add(ctx, job))
+
+			suspended, err := repo.SuspendJobs(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want == cronger.Suspended, len(suspended) == 1)
+
+			jobs, err := repo.JobsByStatus(ctx, tt.want)
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			assert.Equal(t, job.Tag, jobs[0].Tag)
+		})
+	}
+}
+
+func TestMemoryRepositorySetStatusCancelled(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+
+	match := newMemoryJob(cronger.Working)
+	otherFunction := newMemoryJob(cronger.Working)
+	otherFunction.FunctionName = "other"
+	notWorking := newMemoryJob(cronger.Failed)
+	otherID := newMemoryJob(cronger.Working)
+	for _, job := range []cronger.Job{match, otherFunction, notWorking, otherID} {
+		require.NoError(t, repo.Add(ctx, job))
+	}
+
+	tags, err := repo.SetStatusCancelled(ctx, []string{match.ID, otherFunction.ID, notWorking.ID}, "test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{match.Tag}, tags)
+
+	tests := []struct {
+		name string
+		job  cronger.Job
+		want cronger.Status
+	}{
+		{name: "matching job", job: match, want: cronger.Cancelled},
+		{name: "other function", job: otherFunction, want: cronger.Working},
+		{name: "not working", job: notWorking, want: cronger.Failed},
+		{name: "other id", job: otherID, want: cronger.Working},
+	}
+
+	jobs, err := repo.Jobs(ctx)
+	require.NoError(t, err)
+	status := make(map[string]cronger.Status, len(jobs))
+	for _, job := range jobs {
+		status[job.Tag] = job.Status
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status[tt.job.Tag])
+		})
+	}
+}
+
+func TestMemoryRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		value   map[string]interface{}
+		wantErr error
+		check   func(t *testing.T, job cronger.Job)
+	}{
+		{
+			name:  "columns",
+			value: map[string]interface{}{"status": cronger.Failed.String(), "status_description": "error", "attempt": 2},
+			check: func(t *testing.T, job cronger.Job) {
+				assert.Equal(t, cronger.Failed, job.Status)
+				assert.Equal(t, "error", job.StatusDescription)
+				assert.Equal(t, uint(2), job.Attempt)
+			},
+		},
+		{
+			name:    "unknown column",
+			value:   map[string]interface{}{"unknown": 1},
+			wantErr: cronger.ErrUnknownColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			job := newMemoryJob(cronger.Working)
+			require.NoError(t, repo.Add(ctx, job))
+
+			err := repo.Update(ctx, job.Tag, tt.value)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			jobs, err := repo.Jobs(ctx)
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			tt.check(t, jobs[0])
+		})
+	}
+}
+
+func TestMemoryRepositoryRuns(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+
+	tag := uuid.NewString()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 150; i++ {
+		status := cronger.Done
+		if i%2 == 1 {
+			status = cronger.Failed
+		}
+		require.NoError(t, repo.AddRun(ctx, cronger.Run{
+			Tag:       tag,
+			StartedAt: start.Add(time.Duration(i) * time.Minute),
+			Status:    status,
+		}))
+	}
+	require.NoError(t, repo.AddRun(ctx, cronger.Run{Tag: uuid.NewString(), StartedAt: start, Status: cronger.Done}))
+
+	tests := []struct {
+		name   string
+		filter cronger.RunFilter
+		count  int
+		first  time.Time
+	}{
+		{name: "no limit", count: 150, first: start.Add(149 * time.Minute)},
+		{name: "page", filter: cronger.RunFilter{Limit: 10, Offset: 20}, count: 10, first: start.Add(129 * time.Minute)},
+		{name: "last page", filter: cronger.RunFilter{Limit: 100, Offset: 100}, count: 50, first: start.Add(49 * time.Minute)},
+		{name: "offset past the end", filter: cronger.RunFilter{Offset: 200}, count: 0},
+		{name: "status", filter: cronger.RunFilter{Status: cronger.Failed}, count: 75, first: start.Add(149 * time.Minute)},
+		{
+			name:   "range",
+			filter: cronger.RunFilter{From: start.Add(10 * time.Minute), To: start.Add(20 * time.Minute)},
+			count:  10,
+			first:  start.Add(19 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := repo.Runs(ctx, tag, tt.filter)
+			require.NoError(t, err)
+			require.Len(t, runs, tt.count)
+			if tt.count == 0 {
+				return
+			}
+			assert.True(t, tt.first.Equal(runs[0].StartedAt), "first run %s", runs[0].StartedAt)
+			for i := 1; i < len(runs); i++ {
+				assert.True(t, runs[i].StartedAt.Before(runs[i-1].StartedAt))
+			}
+		})
+	}
+}
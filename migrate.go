@@ -0,0 +1,260 @@
+package cronger
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrMigrationNotFound = errors.New("migration not found")
+	ErrInvalidMigration  = errors.New("invalid migration")
+)
+
+const (
+	_migrationsTable = "cronger_migrations"
+	_version         = "version"
+	_migrateUp       = "-- +migrate Up"
+	_migrateDown     = "-- +migrate Down"
+)
+
+//go:embed migration/*.sql
+var migrationFS embed.FS
+
+type migration struct {
+	version uint
+	name    string
+	up      string
+	down    string
+}
+
+// Migrate applies all migrations that have not been applied yet,
+// the options must match the ones passed to NewSqlx.
+func Migrate(ctx context.Context, db *sqlx.DB, opts ...SqlxOption) error {
+	o := newSqlxOptions(opts...)
+	migrations, err := loadMigrations(o)
+	if err != nil {
+		return err
+	}
+	return migrateTo(ctx, db, o, migrations, migrations[len(migrations)-1].version)
+}
+
+// MigrateTo applies or reverts migrations up to the given version,
+// zero reverts all of them.
+func MigrateTo(ctx context.Context, db *sqlx.DB, version uint, opts ...SqlxOption) error {
+	o := newSqlxOptions(opts...)
+	migrations, err := loadMigrations(o)
+	if err != nil {
+		return err
+	}
+
+	found := version == 0
+	for _, m := range migrations {
+		found = found || m.version == version
+	}
+	if !found {
+		return fmt.Errorf("version %d: %w", version, ErrMigrationNotFound)
+	}
+	return migrateTo(ctx, db, o, migrations, version)
+}
+
+// MigrationVersion returns the latest applied migration version.
+func MigrationVersion(ctx context.Context, db *sqlx.DB, opts ...SqlxOption) (uint, error) {
+	o := newSqlxOptions(opts...)
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedMigrations(ctx, conn, o)
+	if err != nil {
+		return 0, err
+	}
+
+	var version uint
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+func migrateTo(ctx context.Context, db *sqlx.DB, o sqlxOptions, migrations []migration, version uint) error {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Replicas started at the same time apply migrations one by one.
+	lockQuery, lockArgs, err := _dialect.Select(
+		goqu.Func("pg_advisory_lock", goqu.Func("hashtextextended", o.quote(o.migrationsTable), 0)),
+	).Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("configure query: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, lockQuery, lockArgs...); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	defer func() {
+		unlockQuery, unlockArgs, _ := _dialect.Select(
+			goqu.Func("pg_advisory_unlock", goqu.Func("hashtextextended", o.quote(o.migrationsTable), 0)),
+		).Prepared(true).ToSQL()
+		if _, err := conn.ExecContext(context.Background(), unlockQuery, unlockArgs...); err != nil {
+			o.logger.Error("unlock migrations", _error, err)
+		}
+	}()
+
+	applied, err := appliedMigrations(ctx, conn, o)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; ok || m.version > version {
+			continue
+		}
+		if err := applyMigration(ctx, conn, o, m, true); err != nil {
+			return err
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.version]; !ok || m.version <= version {
+			continue
+		}
+		if err := applyMigration(ctx, conn, o, m, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, conn *sqlx.Conn, o sqlxOptions) (map[uint]struct{}, error) {
+	if len(o.schema) != 0 {
+		if _, err := conn.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+quoteIdent(o.schema)); err != nil {
+			return nil, fmt.Errorf("create schema: %w", err)
+		}
+	}
+
+	createQuery := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (version int primary key, applied_at timestamptz not null DEFAULT now())",
+		o.quote(o.migrationsTable),
+	)
+	if _, err := conn.ExecContext(ctx, createQuery); err != nil {
+		return nil, fmt.Errorf("create migrations table: %w", err)
+	}
+
+	query, args, err := _dialect.From(o.migrations()).Select(_version).Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("configure query: %w", err)
+	}
+
+	var versions []uint
+	if err := conn.SelectContext(ctx, &versions, query, args...); err != nil {
+		return nil, fmt.Errorf("select migrations: %w", err)
+	}
+
+	applied := make(map[uint]struct{}, len(versions))
+	for _, v := range versions {
+		applied[v] = struct{}{}
+	}
+	return applied, nil
+}
+
+func applyMigration(ctx context.Context, conn *sqlx.Conn, o sqlxOptions, m migration, up bool) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			o.logger.Error("rollback migration", "migration", m.name, _error, err)
+		}
+	}()
+
+	var (
+		statement = m.up
+		query     string
+		args      []interface{}
+	)
+	if up {
+		query, args, err = _dialect.Insert(o.migrations()).
+			Rows(goqu.Record{_version: m.version}).
+			Prepared(true).ToSQL()
+	} else {
+		statement = m.down
+		query, args, err = _dialect.Delete(o.migrations()).
+			Where(goqu.C(_version).Eq(m.version)).
+			Prepared(true).ToSQL()
+	}
+	if err != nil {
+		return fmt.Errorf("configure query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return fmt.Errorf("migration %s: %w", m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("migration %s: %w", m.name, err)
+	}
+	return tx.Commit()
+}
+
+// loadMigrations renders the embedded migrations with the configured identifiers.
+func loadMigrations(o sqlxOptions) ([]migration, error) {
+	entries, err := migrationFS.ReadDir("migration")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: %w", name, ErrInvalidMigration)
+		}
+
+		tmpl, err := template.ParseFS(migrationFS, path.Join("migration", name))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		var data strings.Builder
+		if err := tmpl.Execute(&data, o.templateData()); err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+
+		up, down, ok := strings.Cut(data.String(), _migrateDown)
+		up, ok2 := strings.CutPrefix(strings.TrimSpace(up), _migrateUp)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("migration %s: %w", name, ErrInvalidMigration)
+		}
+
+		migrations = append(migrations, migration{
+			version: uint(version),
+			name:    name,
+			up:      strings.TrimSpace(up),
+			down:    strings.TrimSpace(down),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
@@ -0,0 +1,71 @@
+package cronger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []SqlxOption
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "default",
+			contains: []string{`"jobs"`, `"job_runs"`, `"job_dependencies"`, `"CRONJOB_STATUS"`},
+		},
+		{
+			name: "custom",
+			opts: []SqlxOption{
+				WithSchema("scheduler"),
+				WithTable("billing_jobs"),
+				WithStatusType("BILLING_STATUS"),
+			},
+			contains: []string{
+				`"scheduler"."billing_jobs"`,
+				`"scheduler"."billing_jobs_runs"`,
+				`"scheduler"."billing_jobs_dependencies"`,
+				`"scheduler"."BILLING_STATUS"`,
+				`"billing_jobs_unique_title_operation"`,
+			},
+			excludes: []string{`"jobs"`, `"job_runs"`, `"CRONJOB_STATUS"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(newSqlxOptions(tt.opts...))
+			require.NoError(t, err)
+
+			entries, err := migrationFS.ReadDir("migration")
+			require.NoError(t, err)
+			require.Len(t, migrations, len(entries))
+
+			var all strings.Builder
+			for i, m := range migrations {
+				assert.Equal(t, uint(i+1), m.version, m.name)
+				assert.NotEmpty(t, m.up, m.name)
+				assert.NotEmpty(t, m.down, m.name)
+				for _, section := range []string{m.up, m.down} {
+					assert.NotContains(t, section, "<no value>", m.name)
+					assert.NotContains(t, section, "{{", m.name)
+					assert.NotContains(t, section, "-- +migrate", m.name)
+				}
+				all.WriteString(m.up)
+				all.WriteString(m.down)
+			}
+
+			for _, s := range tt.contains {
+				assert.Contains(t, all.String(), s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, all.String(), s)
+			}
+		})
+	}
+}
@@ -1,29 +1,39 @@
 -- +migrate Up
 
-CREATE TYPE "CRONJOB_STATUS" AS ENUM (
-	'created',
-	'working',
-	'suspended',
-	'done',
-	'failed',
-    'cancelled'
-);
+DO $$ BEGIN
+    CREATE TYPE {{.StatusType}} AS ENUM (
+        'created',
+        'working',
+        'suspended',
+        'done',
+        'failed',
+        'cancelled'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
 
-CREATE TABLE IF NOT EXISTS jobs (
+CREATE TABLE IF NOT EXISTS {{.Jobs}} (
 	tag uuid primary key,
 	id uuid not null,
 	expression varchar(25) not null,
-    status "CRONJOB_STATUS" DEFAULT 'created',
+    status {{.StatusType}} DEFAULT 'created',
     status_description text not null DEFAULT '',
     function_name varchar(50) not null,
     function_fields jsonb not null,
-    limit int DEFAULT 1
+    "limit" int DEFAULT 1
 );
 
-ALTER TABLE jobs ADD CONSTRAINT unique_title_operation UNIQUE (id, function_name);
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS created_at timestamptz not null DEFAULT now();
+
+DO $$ BEGIN
+    ALTER TABLE {{.Jobs}} ADD CONSTRAINT {{.UniqueJob}} UNIQUE (id, function_name);
+EXCEPTION
+    WHEN duplicate_table OR duplicate_object THEN null;
+END $$;
 
 -- +migrate Down
 
-DROP TYPE IF EXISTS "CRONJOB_OPERATION";
+DROP TABLE IF EXISTS {{.Jobs}};
 
-DROP TABLE IF EXISTS jobs;
+DROP TYPE IF EXISTS {{.StatusType}};
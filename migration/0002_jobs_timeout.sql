@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS timeout bigint not null DEFAULT 0;
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS timeout;
@@ -0,0 +1,23 @@
+-- +migrate Up
+
+ALTER TYPE {{.StatusType}} ADD VALUE IF NOT EXISTS 'retrying';
+
+ALTER TABLE {{.Jobs}}
+    ADD COLUMN IF NOT EXISTS max_attempts int not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS backoff varchar(25) not null DEFAULT '',
+    ADD COLUMN IF NOT EXISTS backoff_delay bigint not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS backoff_max bigint not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS jitter double precision not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS attempt int not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at timestamptz;
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}}
+    DROP COLUMN IF EXISTS max_attempts,
+    DROP COLUMN IF EXISTS backoff,
+    DROP COLUMN IF EXISTS backoff_delay,
+    DROP COLUMN IF EXISTS backoff_max,
+    DROP COLUMN IF EXISTS jitter,
+    DROP COLUMN IF EXISTS attempt,
+    DROP COLUMN IF EXISTS next_attempt_at;
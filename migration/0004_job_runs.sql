@@ -0,0 +1,19 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS {{.Runs}} (
+    id bigserial primary key,
+    tag uuid not null,
+    started_at timestamptz not null,
+    finished_at timestamptz not null,
+    duration bigint not null DEFAULT 0,
+    status varchar(25) not null,
+    error text not null DEFAULT '',
+    attempt int not null DEFAULT 0,
+    node varchar(255) not null DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS {{.RunsIndex}} ON {{.Runs}} (tag, started_at DESC);
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS {{.Runs}};
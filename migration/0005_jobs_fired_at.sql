@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS fired_at timestamptz;
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS fired_at;
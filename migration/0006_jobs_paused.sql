@@ -0,0 +1,8 @@
+-- +migrate Up
+
+ALTER TYPE {{.StatusType}} ADD VALUE IF NOT EXISTS 'paused';
+
+-- +migrate Down
+
+-- Enum values cannot be dropped, paused jobs are suspended instead.
+UPDATE {{.Jobs}} SET status = 'suspended' WHERE status = 'paused';
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS run_at timestamptz;
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS run_at;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}}
+    ADD COLUMN IF NOT EXISTS "interval" bigint not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS interval_name varchar(255) not null DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}}
+    DROP COLUMN IF EXISTS "interval",
+    DROP COLUMN IF EXISTS interval_name;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}}
+    ALTER COLUMN expression TYPE varchar(255),
+    ADD COLUMN IF NOT EXISTS expression_format varchar(25) not null DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}}
+    ALTER COLUMN expression TYPE varchar(25),
+    DROP COLUMN IF EXISTS expression_format;
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS location varchar(64) not null DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS location;
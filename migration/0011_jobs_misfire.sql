@@ -0,0 +1,15 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}}
+    ADD COLUMN IF NOT EXISTS misfire_policy varchar(25) not null DEFAULT '',
+    ADD COLUMN IF NOT EXISTS misfire_limit int not null DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_fire_at timestamptz,
+    ADD COLUMN IF NOT EXISTS next_fire_at timestamptz;
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}}
+    DROP COLUMN IF EXISTS misfire_policy,
+    DROP COLUMN IF EXISTS misfire_limit,
+    DROP COLUMN IF EXISTS last_fire_at,
+    DROP COLUMN IF EXISTS next_fire_at;
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS overlap_policy varchar(25) not null DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS overlap_policy;
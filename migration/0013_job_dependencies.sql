@@ -0,0 +1,15 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS {{.Dependencies}} (
+    tag uuid not null REFERENCES {{.Jobs}} (tag) ON DELETE CASCADE,
+    upstream uuid not null REFERENCES {{.Jobs}} (tag) ON DELETE CASCADE,
+    primary key (tag, upstream)
+);
+
+ALTER TABLE {{.Jobs}} ADD COLUMN IF NOT EXISTS dependency_policy varchar(25) not null DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE {{.Jobs}} DROP COLUMN IF EXISTS dependency_policy;
+
+DROP TABLE IF EXISTS {{.Dependencies}};
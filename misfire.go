@@ -0,0 +1,122 @@
+package cronger
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type MisfirePolicy string
+
+const (
+	// Occurrences missed during downtime are not run.
+	MisfireSkip MisfirePolicy = "skip"
+	// The latest missed occurrence is run once.
+	MisfireRunOnce MisfirePolicy = "run_once"
+	// The latest missed occurrences are run up to MisfireLimit.
+	MisfireRunAll MisfirePolicy = "run_all"
+)
+
+func (p MisfirePolicy) String() string {
+	return string(p)
+}
+
+const (
+	_misfireLimit = 100
+	_lastFireAt   = "last_fire_at"
+	_nextFireAt   = "next_fire_at"
+)
+
+// intervalSchedule fires every interval without rounding to seconds.
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// cronSchedule returns the schedule of a cron or interval job in its location.
+func (c *Cronger) cronSchedule(job Job) (cron.Schedule, error) {
+	if len(job.Expression) == 0 {
+		interval, err := c.interval(job)
+		if err != nil {
+			return nil, err
+		}
+		return intervalSchedule(interval), nil
+	}
+
+	return parseIn(job, c.location())
+}
+
+// missed returns the occurrences from the stored next fire time until now
+// to run according to the misfire policy.
+func (c *Cronger) missed(job Job, now time.Time) ([]time.Time, error) {
+	if job.NextFireAt == nil || job.RunAt != nil || !job.NextFireAt.Before(now) {
+		return nil, nil
+	}
+
+	limit := 0
+	switch job.Misfire {
+	case MisfireRunOnce:
+		limit = 1
+	case MisfireRunAll:
+		limit = int(job.MisfireLimit)
+		if limit == 0 {
+			limit = _misfireLimit
+		}
+	default:
+		return nil, nil
+	}
+	if job.Limit != Unlimited && int(job.remaining()) < limit {
+		limit = int(job.remaining())
+	}
+
+	schedule, err := c.cronSchedule(job)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the latest occurrences are kept.
+	var times []time.Time
+	for t := *job.NextFireAt; !t.IsZero() && t.Before(now); t = schedule.Next(t) {
+		times = append(times, t)
+		if len(times) > limit {
+			times = times[1:]
+		}
+	}
+	return times, nil
+}
+
+// catchUp runs the missed occurrences one by one while the job is scheduled.
+func (c *Cronger) catchUp(job Job, fnc func(ctx context.Context) error, times []time.Time) {
+	for _, at := range times {
+		c.mu.Lock()
+		fields, ok := c.scheduled[job.Tag]
+		c.mu.Unlock()
+		if !ok || fields.Status == Paused || c.isClosed() {
+			return
+		}
+
+		c.logger.Info("run missed occurrence", jobAttrs(job, _scheduledAt, at)...)
+		c.fire(job, fnc, at, true)
+	}
+}
+
+// recordFire stores the time of the occurrence and the following one.
+func (c *Cronger) recordFire(job Job, scheduledAt time.Time) {
+	value := map[string]interface{}{
+		_lastFireAt: scheduledAt,
+		_nextFireAt: nil,
+	}
+	if job.RunAt == nil {
+		if schedule, err := c.cronSchedule(job); err == nil {
+			if next := schedule.Next(scheduledAt); !next.IsZero() {
+				value[_nextFireAt] = next
+			}
+		}
+	}
+
+	if err := c.update(job.Tag, value); err != nil {
+		c.logger.Error("update fire time", jobAttrs(job, _scheduledAt, scheduledAt, _error, err)...)
+	}
+}
@@ -0,0 +1,223 @@
+package cronger_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestMisfirePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy cronger.MisfirePolicy
+		limit  uint
+		runs   int32
+		next   bool
+	}{
+		{name: "skip", policy: cronger.MisfireSkip, limit: 10, runs: 0, next: true},
+		{name: "default", limit: 10, runs: 0, next: true},
+		{name: "run_once", policy: cronger.MisfireRunOnce, limit: 10, runs: 1, next: true},
+		{name: "run_all", policy: cronger.MisfireRunAll, limit: 10, runs: 3, next: true},
+		{name: "run_all_limit", policy: cronger.MisfireRunAll, limit: 2, runs: 2, next: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+			defer cr.Shutdown(context.Background())
+
+			// Four occurrences were missed while the instance was down.
+			nextFireAt := time.Now().Add(-210 * time.Minute)
+			job := cronger.Job{
+				Tag:            uuid.NewString(),
+				ID:             uuid.NewString(),
+				Interval:       time.Hour,
+				FunctionName:   "test",
+				FunctionFields: cronger.FunctionFields{},
+				Limit:          tt.limit,
+				Misfire:        tt.policy,
+				MisfireLimit:   3,
+				NextFireAt:     &nextFireAt,
+			}
+
+			var runs atomic.Int32
+			require.NoError(t, cr.Add(cronger.Fields{
+				Job: job,
+				Task: func(context.Context) error {
+					runs.Add(1)
+					return nil
+				},
+			}))
+
+			assert.Eventually(t, func() bool {
+				history, err := repo.Runs(context.Background(), job.Tag, cronger.RunFilter{})
+				return err == nil && len(history) == int(tt.runs)
+			}, time.Second, 10*time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, tt.runs, runs.Load())
+
+			next, err := cr.NextRuns(job.Tag, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, len(next) == 1)
+
+			jobs, err := repo.Jobs(context.Background())
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			if tt.next {
+				require.NotNil(t, jobs[0].NextFireAt)
+				assert.True(t, jobs[0].NextFireAt.After(time.Now()))
+			}
+			if tt.runs != 0 {
+				require.NotNil(t, jobs[0].LastFireAt)
+				assert.WithinDuration(t, time.Now().Add(-30*time.Minute), *jobs[0].LastFireAt, time.Second)
+			}
+		})
+	}
+}
+
+func TestMisfireFixedZone(t *testing.T) {
+	// A zone without a name, its hours start at half past in UTC.
+	loc := time.FixedZone("UTC+05:30", 5*3600+1800)
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        loc,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	nextFireAt := time.Now().Add(-150 * time.Minute)
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 * * * *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          10,
+		Misfire:        cronger.MisfireRunOnce,
+		NextFireAt:     &nextFireAt,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+	assert.Eventually(t, func() bool {
+		history, err := repo.Runs(context.Background(), job.Tag, cronger.RunFilter{})
+		return err == nil && len(history) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	jobs, err := repo.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.NotNil(t, jobs[0].LastFireAt)
+	assert.Equal(t, 30, jobs[0].LastFireAt.UTC().Minute())
+	require.NotNil(t, jobs[0].NextFireAt)
+	assert.Equal(t, 30, jobs[0].NextFireAt.UTC().Minute())
+}
+
+func TestMisfireRestart(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	registry := cronger.NewRegistry()
+	runs := make(map[string]*atomic.Int32)
+	require.NoError(t, registry.Register("misfire", func(_ context.Context, job cronger.Job, _ cronger.FunctionFields) error {
+		runs[job.Tag].Add(1)
+		return nil
+	}))
+	config := func() *cronger.Config {
+		return &cronger.Config{
+			Loc:        time.UTC,
+			Repository: repo,
+			Registry:   registry,
+		}
+	}
+
+	// The jobs fire at the first second and every second from the third to the
+	// fifth one, the instance is down for the last three.
+	start := time.Now().UTC().Truncate(time.Second).Add(time.Second)
+	if start.Second() > 50 {
+		start = start.Truncate(time.Minute).Add(time.Minute)
+	}
+	expression := fmt.Sprintf("%d,%d-%d %d %d %d %d *",
+		start.Second(), start.Second()+2, start.Second()+4, start.Minute(), start.Hour(), start.Day(), start.Month())
+
+	tests := []struct {
+		name   string
+		policy cronger.MisfirePolicy
+		runs   int32
+	}{
+		{name: "skip", policy: cronger.MisfireSkip, runs: 1},
+		{name: "run_once", policy: cronger.MisfireRunOnce, runs: 2},
+		{name: "run_all", policy: cronger.MisfireRunAll, runs: 3},
+	}
+
+	cr, err := cronger.New(config())
+	require.NoError(t, err)
+	jobs := make(map[string]cronger.Job, len(tests))
+	for _, tt := range tests {
+		job := cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			Expression:     expression,
+			FunctionName:   "misfire",
+			FunctionFields: cronger.FunctionFields{},
+			Limit:          10,
+			Misfire:        tt.policy,
+			MisfireLimit:   2,
+		}
+		runs[job.Tag] = &atomic.Int32{}
+		require.NoError(t, cr.AddJob(job))
+		jobs[tt.name] = job
+	}
+
+	require.Eventually(t, func() bool {
+		for _, job := range jobs {
+			if runs[job.Tag].Load() != 1 {
+				return false
+			}
+		}
+		return true
+	}, 15*time.Second, 10*time.Millisecond)
+	require.NoError(t, cr.Shutdown(ctx))
+
+	time.Sleep(time.Until(start.Add(5 * time.Second)))
+	cr, err = cronger.New(config())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := jobs[tt.name]
+			assert.Eventually(t, func() bool {
+				history, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{})
+				return err == nil && len(history) == int(tt.runs)
+			}, time.Second, 10*time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, tt.runs, runs[job.Tag].Load())
+
+			stored, err := repo.Jobs(ctx)
+			require.NoError(t, err)
+			for _, s := range stored {
+				if s.Tag != job.Tag {
+					continue
+				}
+				assert.Equal(t, cronger.Working, s.Status)
+				require.NotNil(t, s.LastFireAt)
+				if tt.policy != cronger.MisfireSkip {
+					assert.True(t, start.Add(4*time.Second).Equal(*s.LastFireAt), "last fire %s", s.LastFireAt)
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	cronger "github.com/vladjong/cronger"
+
+	time "time"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -28,6 +30,46 @@ func (_m *Repository) Add(ctx context.Context, in cronger.Job) error {
 	return r0
 }
 
+// AddRun provides a mock function with given fields: ctx, in
+func (_m *Repository) AddRun(ctx context.Context, in cronger.Run) error {
+	ret := _m.Called(ctx, in)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, cronger.Run) error); ok {
+		r0 = rf(ctx, in)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Dependencies provides a mock function with given fields: ctx
+func (_m *Repository) Dependencies(ctx context.Context) ([]cronger.Dependency, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []cronger.Dependency
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]cronger.Dependency, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []cronger.Dependency); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cronger.Dependency)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Jobs provides a mock function with given fields: ctx
 func (_m *Repository) Jobs(ctx context.Context) ([]cronger.Job, error) {
 	ret := _m.Called(ctx)
@@ -54,6 +96,32 @@ func (_m *Repository) Jobs(ctx context.Context) ([]cronger.Job, error) {
 	return r0, r1
 }
 
+// JobsByStatus provides a mock function with given fields: ctx, status
+func (_m *Repository) JobsByStatus(ctx context.Context, status cronger.Status) ([]cronger.Job, error) {
+	ret := _m.Called(ctx, status)
+
+	var r0 []cronger.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, cronger.Status) ([]cronger.Job, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, cronger.Status) []cronger.Job); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cronger.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, cronger.Status) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Remove provides a mock function with given fields: ctx, tag
 func (_m *Repository) Remove(ctx context.Context, tag string) error {
 	ret := _m.Called(ctx, tag)
@@ -68,6 +136,46 @@ func (_m *Repository) Remove(ctx context.Context, tag string) error {
 	return r0
 }
 
+// Runs provides a mock function with given fields: ctx, tag, filter
+func (_m *Repository) Runs(ctx context.Context, tag string, filter cronger.RunFilter) ([]cronger.Run, error) {
+	ret := _m.Called(ctx, tag, filter)
+
+	var r0 []cronger.Run
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, cronger.RunFilter) ([]cronger.Run, error)); ok {
+		return rf(ctx, tag, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, cronger.RunFilter) []cronger.Run); ok {
+		r0 = rf(ctx, tag, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cronger.Run)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, cronger.RunFilter) error); ok {
+		r1 = rf(ctx, tag, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDependencies provides a mock function with given fields: ctx, tag, upstream
+func (_m *Repository) SetDependencies(ctx context.Context, tag string, upstream []string) error {
+	ret := _m.Called(ctx, tag, upstream)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, tag, upstream)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetStatusCancelled provides a mock function with given fields: ctx, ids, functionName
 func (_m *Repository) SetStatusCancelled(ctx context.Context, ids []string, functionName string) ([]string, error) {
 	ret := _m.Called(ctx, ids, functionName)
@@ -94,22 +202,8 @@ func (_m *Repository) SetStatusCancelled(ctx context.Context, ids []string, func
 	return r0, r1
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, tag, status
-func (_m *Repository) UpdateStatus(ctx context.Context, tag string, status cronger.Status) error {
-	ret := _m.Called(ctx, tag, status)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, cronger.Status) error); ok {
-		r0 = rf(ctx, tag, status)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// WorkingUpdateSuspend provides a mock function with given fields: ctx
-func (_m *Repository) WorkingUpdateSuspend(ctx context.Context) ([]cronger.Job, error) {
+// SuspendJobs provides a mock function with given fields: ctx
+func (_m *Repository) SuspendJobs(ctx context.Context) ([]cronger.Job, error) {
 	ret := _m.Called(ctx)
 
 	var r0 []cronger.Job
@@ -134,6 +228,58 @@ func (_m *Repository) WorkingUpdateSuspend(ctx context.Context) ([]cronger.Job,
 	return r0, r1
 }
 
+// TryLock provides a mock function with given fields: ctx, tag, scheduledAt
+func (_m *Repository) TryLock(ctx context.Context, tag string, scheduledAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, tag, scheduledAt)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return rf(ctx, tag, scheduledAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(ctx, tag, scheduledAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, tag, scheduledAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, tag, in
+func (_m *Repository) Update(ctx context.Context, tag string, in map[string]interface{}) error {
+	ret := _m.Called(ctx, tag, in)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) error); ok {
+		r0 = rf(ctx, tag, in)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, tag, status
+func (_m *Repository) UpdateStatus(ctx context.Context, tag string, status cronger.Status) error {
+	ret := _m.Called(ctx, tag, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, cronger.Status) error); ok {
+		r0 = rf(ctx, tag, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,19 @@
+package cronger
+
+import "time"
+
+// AddAt adds a one-off job run once at the given time, a time missed while
+// the job was suspended is run on restore.
+func (c *Cronger) AddAt(at time.Time, in Fields) error {
+	if c.cfg.Loc != nil {
+		at = at.In(c.cfg.Loc)
+	}
+	in.Expression = ""
+	in.RunAt = &at
+	return c.Add(in)
+}
+
+// AddAfter adds a one-off job run once after the given duration.
+func (c *Cronger) AddAfter(d time.Duration, in Fields) error {
+	return c.AddAt(time.Now().Add(d), in)
+}
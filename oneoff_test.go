@@ -0,0 +1,84 @@
+package cronger_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestAddAfter(t *testing.T) {
+	repo := cronger.NewMemory()
+	var runs atomic.Int32
+	registry := cronger.NewRegistry()
+	require.NoError(t, registry.Register("once", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		runs.Add(1)
+		return nil
+	}))
+	newCronger := func() *cronger.Cronger {
+		cr, err := cronger.New(&cronger.Config{
+			Loc:        time.UTC,
+			Repository: repo,
+			Registry:   registry,
+		})
+		require.NoError(t, err)
+		return cr
+	}
+	status := func(tag string) cronger.Status {
+		jobs, err := repo.Jobs(context.Background())
+		require.NoError(t, err)
+		for _, job := range jobs {
+			if job.Tag == tag {
+				return job.Status
+			}
+		}
+		return ""
+	}
+	newJob := func() cronger.Job {
+		return cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			FunctionName:   "once",
+			FunctionFields: cronger.FunctionFields{},
+		}
+	}
+
+	cr := newCronger()
+	job := newJob()
+	require.NoError(t, cr.AddAfter(50*time.Millisecond, cronger.Fields{
+		Job:  job,
+		Task: func(context.Context) error { runs.Add(1); return nil },
+	}))
+	assert.Eventually(t, func() bool { return status(job.Tag) == cronger.Done }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load())
+
+	missed := newJob()
+	require.NoError(t, cr.AddAfter(time.Hour, cronger.Fields{Job: missed, Task: func(context.Context) error { return nil }}))
+	require.NoError(t, cr.Shutdown(context.Background()))
+
+	// The time is missed while the instance is down.
+	jobs, err := repo.Jobs(context.Background())
+	require.NoError(t, err)
+	for _, j := range jobs {
+		if j.Tag == missed.Tag {
+			past := time.Now().Add(-time.Minute)
+			require.NoError(t, repo.Update(context.Background(), j.Tag, map[string]interface{}{"run_at": past}))
+		}
+	}
+
+	cr = newCronger()
+	defer cr.Shutdown(context.Background())
+	assert.Eventually(t, func() bool { return status(missed.Tag) == cronger.Done }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), runs.Load())
+
+	invalid := newJob()
+	invalid.Limit = 1
+	err = cr.Add(cronger.Fields{Job: invalid, Task: func(context.Context) error { return nil }})
+	assert.ErrorIs(t, err, cronger.ErrInvalidSchedule)
+}
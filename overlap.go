@@ -0,0 +1,158 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRunOverlap  = errors.New("previous run is still executing")
+	ErrJobReplaced = errors.New("job run replaced by the next occurrence")
+)
+
+type OverlapPolicy string
+
+const (
+	// Occurrences run concurrently with the previous run.
+	OverlapAllow OverlapPolicy = "allow"
+	// Occurrences are skipped while the previous run is executing.
+	OverlapSkip OverlapPolicy = "skip"
+	// One occurrence waits for the previous run, the others are skipped.
+	OverlapQueue OverlapPolicy = "queue"
+	// The previous run is cancelled and the occurrence starts after it returns.
+	OverlapReplace OverlapPolicy = "replace"
+)
+
+func (p OverlapPolicy) String() string {
+	return string(p)
+}
+
+// overlapGate lets a single run of the job execute at a time.
+type overlapGate struct {
+	active chan struct{}
+	queued bool
+}
+
+func (c *Cronger) gate(tag string) *overlapGate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, ok := c.gates[tag]
+	if !ok {
+		g = &overlapGate{active: make(chan struct{}, 1)}
+		c.gates[tag] = g
+	}
+	return g
+}
+
+// acquire takes the gate of the job by its overlap policy, false when
+// the occurrence is skipped.
+func (c *Cronger) acquire(job Job) (func(), bool) {
+	g := c.gate(job.Tag)
+	release := func() { <-g.active }
+
+	select {
+	case g.active <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	switch job.Overlap {
+	case OverlapSkip:
+		return nil, false
+	case OverlapQueue:
+		c.mu.Lock()
+		if g.queued {
+			c.mu.Unlock()
+			return nil, false
+		}
+		g.queued = true
+		c.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			g.queued = false
+			c.mu.Unlock()
+		}()
+	case OverlapReplace:
+		c.cancelRuns(job.Tag, ErrJobReplaced)
+	}
+
+	select {
+	case g.active <- struct{}{}:
+		return release, true
+	case <-c.ctx.Done():
+		return nil, false
+	}
+}
+
+// notRun records the occurrence that was skipped or dropped with the reason.
+func (c *Cronger) notRun(job Job, scheduledAt time.Time, status Status, reason error) {
+	c.logger.Info("job run "+status.String(), jobAttrs(job, _scheduledAt, scheduledAt, _error, reason)...)
+
+	now := time.Now()
+	run := Run{
+		Tag:        job.Tag,
+		StartedAt:  now,
+		FinishedAt: now,
+		Status:     status,
+		Error:      reason.Error(),
+		Attempt:    job.Attempt,
+		Node:       c.node,
+	}
+	if err := c.addRun(run); err != nil {
+		c.logger.Error("add run", jobAttrs(job, _status, run.Status, _error, err)...)
+	}
+}
+
+// skip records the skipped occurrence, a counted one uses a run of the limit
+// since the schedule counts it, and the job is done when it is the last one.
+func (c *Cronger) skip(job Job, scheduledAt time.Time, reason error, counted bool) {
+	c.notRun(job, scheduledAt, Skipped, reason)
+	if !counted {
+		return
+	}
+
+	value := map[string]interface{}{_fireCount: c.countFire(job.Tag)}
+	c.mu.Lock()
+	fields, ok := c.scheduled[job.Tag]
+	c.mu.Unlock()
+	done := ok && fields.Status == Working && c.limitUsed(job)
+	if done {
+		value[_status] = Done.String()
+	}
+
+	if err := c.update(job.Tag, value); err != nil {
+		c.logger.Error("update job status", jobAttrs(job, _status, value[_status], _error, err)...)
+	} else if done {
+		c.changeStatus(job, Done)
+	}
+}
+
+// recordCount counts the occurrence that did not finish its run against the
+// limit of the job.
+func (c *Cronger) recordCount(job Job) {
+	if err := c.update(job.Tag, map[string]interface{}{_fireCount: c.countFire(job.Tag)}); err != nil {
+		c.logger.Error("update fire count", jobAttrs(job, _error, err)...)
+	}
+}
+
+// runExclusive runs the task of the occurrence by the overlap policy of the job.
+func (c *Cronger) runExclusive(job Job, fnc func(ctx context.Context) error, scheduledAt time.Time, counted bool) {
+	release := func() {}
+	if len(job.Overlap) != 0 && job.Overlap != OverlapAllow {
+		var ok bool
+		if release, ok = c.acquire(job); !ok {
+			if c.ctx.Err() == nil {
+				c.skip(job, scheduledAt, ErrRunOverlap, counted)
+			}
+			return
+		}
+	}
+
+	c.submit(job, scheduledAt, counted, func() {
+		defer release()
+		c.runTask(job, fnc, counted)
+	}, release)
+}
@@ -0,0 +1,142 @@
+package cronger_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     cronger.OverlapPolicy
+		concurrent bool
+		status     cronger.Status
+	}{
+		{name: "allow", policy: cronger.OverlapAllow, concurrent: true},
+		{name: "skip", policy: cronger.OverlapSkip, status: cronger.Skipped},
+		{name: "queue", policy: cronger.OverlapQueue, status: cronger.Skipped},
+		{name: "replace", policy: cronger.OverlapReplace, status: cronger.Cancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+
+			job := cronger.Job{
+				Tag:            uuid.NewString(),
+				ID:             uuid.NewString(),
+				Interval:       20 * time.Millisecond,
+				FunctionName:   "test",
+				FunctionFields: cronger.FunctionFields{},
+				Limit:          10,
+				Overlap:        tt.policy,
+			}
+
+			var running, maxRunning atomic.Int32
+			require.NoError(t, cr.Add(cronger.Fields{
+				Job: job,
+				Task: func(ctx context.Context) error {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						max := maxRunning.Load()
+						if n <= max || maxRunning.CompareAndSwap(max, n) {
+							break
+						}
+					}
+
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(70 * time.Millisecond):
+						return nil
+					}
+				},
+			}))
+
+			time.Sleep(300 * time.Millisecond)
+			require.NoError(t, cr.Shutdown(context.Background()))
+
+			assert.Equal(t, tt.concurrent, maxRunning.Load() > 1)
+			if len(tt.status) != 0 {
+				runs, err := repo.Runs(context.Background(), job.Tag, cronger.RunFilter{Status: tt.status})
+				require.NoError(t, err)
+				assert.NotEmpty(t, runs)
+			}
+		})
+	}
+}
+
+func TestOverlapLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy cronger.OverlapPolicy
+	}{
+		{name: "skip", policy: cronger.OverlapSkip},
+		{name: "queue", policy: cronger.OverlapQueue},
+		{name: "replace", policy: cronger.OverlapReplace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := cronger.NewMemory()
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+			job := cronger.Job{
+				Tag:            uuid.NewString(),
+				ID:             uuid.NewString(),
+				Interval:       50 * time.Millisecond,
+				FunctionName:   "test",
+				FunctionFields: cronger.FunctionFields{},
+				Limit:          3,
+				Overlap:        tt.policy,
+			}
+			require.NoError(t, cr.Add(cronger.Fields{
+				Job: job,
+				Task: func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(80 * time.Millisecond):
+						return nil
+					}
+				},
+			}))
+
+			// Skipped and replaced occurrences count against the limit.
+			require.Eventually(t, func() bool {
+				jobs, err := repo.Jobs(ctx)
+				require.NoError(t, err)
+				return jobs[0].Status == cronger.Done
+			}, 2*time.Second, 10*time.Millisecond)
+			time.Sleep(150 * time.Millisecond)
+
+			runs, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{})
+			require.NoError(t, err)
+			assert.Len(t, runs, int(job.Limit))
+			jobs, err := repo.Jobs(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, cronger.Done, jobs[0].Status)
+			assert.Equal(t, job.Limit, jobs[0].FireCount)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-co-op/gocron"
+)
+
+var (
+	ErrJobNotFound  = errors.New("job not found")
+	ErrJobNotPaused = errors.New("job is not paused")
+	ErrJobPaused    = errors.New("job paused")
+)
+
+// Pause detaches the job from the schedule and stores it with the paused status
+// and the runs counted against its limit, running tasks are cancelled. Paused
+// jobs are not suspended on restart and stay paused until Resume. A job that
+// has used its limit is stored as done.
+func (c *Cronger) Pause(tag string) error {
+	c.mu.Lock()
+	fields, ok := c.scheduled[tag]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("pause job %s: %w", tag, ErrJobNotFound)
+	}
+	if fields.Status == Paused {
+		return nil
+	}
+
+	// The job is not in the schedule while its last runs catch up.
+	if err := c.schedule.RemoveByTag(tag); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		return fmt.Errorf("pause job %s: %w", tag, err)
+	}
+	c.stopRetry(tag)
+	c.cancelRuns(tag, ErrJobPaused)
+
+	// A job without remaining runs is done rather than paused.
+	c.mu.Lock()
+	fields.FireCount = c.fired[tag]
+	c.mu.Unlock()
+	status := Paused
+	if fields.Limit != Unlimited && fields.remaining() == 0 {
+		status = Done
+	}
+
+	if err := c.update(tag, map[string]interface{}{
+		_status:    status.String(),
+		_fireCount: fields.FireCount,
+		// Occurrences are not missed while the job is paused.
+		_nextFireAt: nil,
+	}); err != nil {
+		return fmt.Errorf("pause job %s: %w", tag, err)
+	}
+
+	// Resume counts from the stored runs.
+	c.mu.Lock()
+	delete(c.fired, tag)
+	c.mu.Unlock()
+	c.changeStatus(fields.Job, status)
+	return nil
+}
+
+// Resume adds the paused job back to the schedule, jobs paused before a restart
+// are resumed with the registered handler.
+func (c *Cronger) Resume(tag string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	jobs, err := c.cfg.Repository.JobsByStatus(ctx, Paused)
+	if err != nil {
+		return fmt.Errorf("resume job %s: %w", tag, err)
+	}
+
+	var (
+		job   Job
+		found bool
+	)
+	for _, paused := range jobs {
+		if paused.Tag == tag {
+			job, found = paused, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("resume job %s: %w", tag, ErrJobNotPaused)
+	}
+
+	c.mu.Lock()
+	fields, ok := c.scheduled[tag]
+	c.mu.Unlock()
+	if !ok {
+		return c.AddJob(job)
+	}
+	return c.Add(Fields{Job: job, Task: fields.Task})
+}
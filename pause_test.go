@@ -0,0 +1,125 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestPauseResume(t *testing.T) {
+	repo := cronger.NewMemory()
+	registry := cronger.NewRegistry()
+	require.NoError(t, registry.Register("test", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		return nil
+	}))
+
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+		Registry:   registry,
+	})
+	require.NoError(t, err)
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          3,
+	}
+	require.NoError(t, cr.AddJob(job))
+
+	status := func() cronger.Status {
+		jobs, err := repo.Jobs(context.Background())
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, job.Limit, jobs[0].Limit)
+		return jobs[0].Status
+	}
+
+	assert.ErrorIs(t, cr.Pause(uuid.NewString()), cronger.ErrJobNotFound)
+	assert.ErrorIs(t, cr.Resume(job.Tag), cronger.ErrJobNotPaused)
+
+	require.NoError(t, cr.Pause(job.Tag))
+	require.NoError(t, cr.Pause(job.Tag))
+	assert.Equal(t, cronger.Paused, status())
+
+	// Paused jobs are not suspended on restart.
+	require.NoError(t, cr.Shutdown(context.Background()))
+	cr, err = cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+		Registry:   registry,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, cr.SuspendJobs())
+	assert.Equal(t, cronger.Paused, status())
+
+	require.NoError(t, cr.Resume(job.Tag))
+	assert.Equal(t, cronger.Working, status())
+	require.NoError(t, cr.Shutdown(context.Background()))
+}
+
+func TestPauseLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  uint
+		runs   int
+		status cronger.Status
+		next   int
+	}{
+		{name: "runs remain", limit: 3, runs: 1, status: cronger.Paused, next: 2},
+		{name: "limit used", limit: 2, runs: 2, status: cronger.Done},
+		{name: "limit exceeded", limit: 1, runs: 2, status: cronger.Done},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := cronger.NewMemory()
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+			job := cronger.Job{
+				Tag:            uuid.NewString(),
+				ID:             uuid.NewString(),
+				Expression:     "0 0 1 1 *",
+				FunctionName:   "test",
+				FunctionFields: cronger.FunctionFields{},
+				Limit:          tt.limit,
+			}
+			task := func(context.Context) error { return nil }
+			require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: task}))
+			for i := 0; i < tt.runs; i++ {
+				cr.Template(job, task)
+			}
+
+			// The stored limit is kept, so an update applies on Resume.
+			require.NoError(t, cr.Pause(job.Tag))
+			jobs, err := repo.Jobs(context.Background())
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			assert.Equal(t, tt.status, jobs[0].Status)
+			assert.Equal(t, tt.limit, jobs[0].Limit)
+			assert.Equal(t, uint(tt.runs), jobs[0].FireCount)
+			if tt.status != cronger.Paused {
+				return
+			}
+
+			require.NoError(t, cr.Resume(job.Tag))
+			times, err := cr.NextRuns(job.Tag, 5)
+			require.NoError(t, err)
+			assert.Len(t, times, tt.next)
+		})
+	}
+}
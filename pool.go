@@ -0,0 +1,210 @@
+package cronger
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrQueueFull = errors.New("run queue is full")
+
+type OverflowPolicy string
+
+const (
+	// Occurrences are skipped when the queue is full.
+	OverflowSkip OverflowPolicy = "skip"
+	// Firing waits for a place in the queue.
+	OverflowWait OverflowPolicy = "wait"
+)
+
+func (p OverflowPolicy) String() string {
+	return string(p)
+}
+
+const (
+	_queueSize = 1000
+)
+
+// Stats of the worker pool, empty when no concurrency limit is set.
+type Stats struct {
+	Running           int
+	Queued            int
+	RunningByFunction map[string]int
+	QueuedByFunction  map[string]int
+}
+
+// pool starts the runs within the global and per-function limits, the others
+// wait in the queue in order of firing.
+type pool struct {
+	mu   sync.Mutex
+	room *sync.Cond
+
+	max           int
+	maxByFunction map[string]int
+	queueSize     int
+	overflow      OverflowPolicy
+
+	pending           []poolTask
+	running           int
+	runningByFunction map[string]int
+	closed            bool
+}
+
+type poolTask struct {
+	function string
+	run      func()
+	drop     func()
+}
+
+// newPool returns nil when no concurrency limit is set.
+func newPool(cfg *Config) *pool {
+	if cfg.MaxConcurrent <= 0 && len(cfg.MaxConcurrentByFunction) == 0 {
+		return nil
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = _queueSize
+	}
+
+	p := &pool{
+		max:               cfg.MaxConcurrent,
+		maxByFunction:     cfg.MaxConcurrentByFunction,
+		queueSize:         queueSize,
+		overflow:          cfg.Overflow,
+		runningByFunction: make(map[string]int),
+	}
+	p.room = sync.NewCond(&p.mu)
+	return p
+}
+
+// submit starts or queues the run, false when it is rejected by the overflow
+// policy or the pool is closed. Dropped runs are passed to drop on close.
+func (p *pool) submit(function string, run, drop func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	task := poolTask{function: function, run: run, drop: drop}
+	for {
+		switch {
+		case p.closed:
+			return false
+		case p.canStart(function):
+			p.start(task)
+			return true
+		case len(p.pending) < p.queueSize:
+			p.pending = append(p.pending, task)
+			return true
+		case p.overflow != OverflowWait:
+			return false
+		}
+		p.room.Wait()
+	}
+}
+
+func (p *pool) canStart(function string) bool {
+	if p.max > 0 && p.running >= p.max {
+		return false
+	}
+	if limit := p.maxByFunction[function]; limit > 0 && p.runningByFunction[function] >= limit {
+		return false
+	}
+	return true
+}
+
+func (p *pool) start(task poolTask) {
+	p.running++
+	p.runningByFunction[task.function]++
+
+	go func() {
+		defer p.finish(task.function)
+		task.run()
+	}()
+}
+
+func (p *pool) finish(function string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running--
+	p.runningByFunction[function]--
+	if p.runningByFunction[function] == 0 {
+		delete(p.runningByFunction, function)
+	}
+
+	// Runs of other functions are not held up by a function at its limit.
+	pending := p.pending[:0]
+	for _, task := range p.pending {
+		if !p.closed && p.canStart(task.function) {
+			p.start(task)
+			continue
+		}
+		pending = append(pending, task)
+	}
+	for i := len(pending); i < len(p.pending); i++ {
+		p.pending[i] = poolTask{}
+	}
+	p.pending = pending
+	p.room.Broadcast()
+}
+
+// close rejects the following runs and drops the queued ones.
+func (p *pool) close() {
+	p.mu.Lock()
+	p.closed = true
+	dropped := p.pending
+	p.pending = nil
+	p.room.Broadcast()
+	p.mu.Unlock()
+
+	for _, task := range dropped {
+		task.drop()
+	}
+}
+
+func (p *pool) stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := Stats{
+		Running:           p.running,
+		Queued:            len(p.pending),
+		RunningByFunction: make(map[string]int, len(p.runningByFunction)),
+		QueuedByFunction:  make(map[string]int),
+	}
+	for function, running := range p.runningByFunction {
+		stats.RunningByFunction[function] = running
+	}
+	for _, task := range p.pending {
+		stats.QueuedByFunction[task.function]++
+	}
+	return stats
+}
+
+// Stats returns the running and queued runs of the worker pool.
+func (c *Cronger) Stats() Stats {
+	if c.pool == nil {
+		return Stats{}
+	}
+	return c.pool.stats()
+}
+
+// submit runs the occurrence within the concurrency limits, release is called
+// when it is not run.
+func (c *Cronger) submit(job Job, scheduledAt time.Time, counted bool, run, release func()) {
+	if c.pool == nil {
+		run()
+		return
+	}
+
+	drop := func() {
+		release()
+		c.notRun(job, scheduledAt, Suspended, ErrShutdown)
+	}
+	if !c.pool.submit(job.FunctionName, run, drop) {
+		release()
+		if !c.isClosed() {
+			c.skip(job, scheduledAt, ErrQueueFull, counted)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:                     time.UTC,
+		Repository:              repo,
+		MaxConcurrent:           2,
+		MaxConcurrentByFunction: map[string]int{"slow": 1},
+		QueueSize:               2,
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	task := func(ctx context.Context) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	var tags []string
+	for _, function := range []string{"slow", "slow", "slow", "slow", "fast"} {
+		job := cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			FunctionName:   function,
+			FunctionFields: cronger.FunctionFields{},
+		}
+		require.NoError(t, cr.AddAfter(50*time.Millisecond, cronger.Fields{Job: job, Task: task}))
+		tags = append(tags, job.Tag)
+	}
+
+	// The fast run is not held up by the queued slow ones.
+	assert.Eventually(t, func() bool {
+		stats := cr.Stats()
+		return stats.Running == 2 && stats.Queued == 2
+	}, time.Second, 10*time.Millisecond)
+	stats := cr.Stats()
+	assert.Equal(t, map[string]int{"slow": 1, "fast": 1}, stats.RunningByFunction)
+	assert.Equal(t, map[string]int{"slow": 2}, stats.QueuedByFunction)
+
+	assert.Eventually(t, func() bool {
+		var skipped int
+		for _, tag := range tags {
+			runs, err := repo.Runs(context.Background(), tag, cronger.RunFilter{Status: cronger.Skipped})
+			require.NoError(t, err)
+			skipped += len(runs)
+		}
+		return skipped == 1
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		stats := cr.Stats()
+		return stats.Running == 0 && stats.Queued == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestConcurrencyOverflowLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:           time.UTC,
+		Repository:    repo,
+		MaxConcurrent: 1,
+		QueueSize:     1,
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	blocker := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		FunctionName:   "blocker",
+		FunctionFields: cronger.FunctionFields{},
+	}
+	require.NoError(t, cr.AddAfter(time.Millisecond, cronger.Fields{Job: blocker, Task: func(context.Context) error {
+		<-release
+		return nil
+	}}))
+	require.Eventually(t, func() bool { return cr.Stats().Running == 1 }, time.Second, 10*time.Millisecond)
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Interval:       50 * time.Millisecond,
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          3,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+	// Dropped occurrences count against the limit.
+	require.Eventually(t, func() bool {
+		runs, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{Status: cronger.Skipped})
+		require.NoError(t, err)
+		return len(runs) == 2
+	}, time.Second, 10*time.Millisecond)
+	close(release)
+
+	require.Eventually(t, func() bool {
+		jobs, err := repo.JobsByStatus(ctx, cronger.Done)
+		require.NoError(t, err)
+		for _, done := range jobs {
+			if done.Tag == job.Tag {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	runs, err := repo.Runs(ctx, job.Tag, cronger.RunFilter{})
+	require.NoError(t, err)
+	assert.Len(t, runs, int(job.Limit))
+}
@@ -0,0 +1,103 @@
+package cronger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// NextRuns returns up to n next fire times of the scheduled job in its location,
+// none for a paused, finished or dependent job.
+func (c *Cronger) NextRuns(tag string, n int) ([]time.Time, error) {
+	c.mu.Lock()
+	fields, ok := c.scheduled[tag]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("next runs of job %s: %w", tag, ErrJobNotFound)
+	}
+
+	jobs, err := c.schedule.FindJobsByTag(tag)
+	if err != nil || len(jobs) == 0 || fields.Status == Paused {
+		return nil, nil
+	}
+	gj := jobs[0]
+
+	if fields.Limit != Unlimited {
+		c.mu.Lock()
+		fields.FireCount = c.fired[tag]
+		c.mu.Unlock()
+		if remaining := int(fields.remaining()); remaining < n {
+			n = remaining
+		}
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	job := fields.Job
+	loc := c.location()
+	if len(job.Location) != 0 {
+		if loc, err = time.LoadLocation(job.Location); err != nil {
+			return nil, fmt.Errorf("next runs of job %s: %w", tag, err)
+		}
+	}
+
+	next := gj.NextRun().In(loc)
+	switch {
+	case job.RunAt != nil:
+		return []time.Time{next}, nil
+	case len(job.Expression) != 0:
+		schedule, err := parseIn(job, loc)
+		if err != nil {
+			return nil, err
+		}
+		// The zone of a CRON_TZ prefix is set by the parser.
+		if spec, ok := schedule.(*cron.SpecSchedule); ok {
+			next = next.In(spec.Location)
+		}
+		return append([]time.Time{next}, nextTimes(schedule, next, n-1)...), nil
+	default:
+		interval, err := c.interval(job)
+		if err != nil {
+			return nil, err
+		}
+		times := make([]time.Time, n)
+		for i := range times {
+			times[i] = next.Add(time.Duration(i) * interval)
+		}
+		return times, nil
+	}
+}
+
+// PreviewExpression returns n fire times of the expression after from in loc,
+// to check a schedule before Add.
+func PreviewExpression(expression string, loc *time.Location, from time.Time, n int) ([]time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	schedule, err := parseIn(Job{Expression: expression}, loc)
+	if err != nil {
+		return nil, err
+	}
+	return nextTimes(schedule, from, n), nil
+}
+
+func nextTimes(schedule cron.Schedule, from time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for t := from; len(times) < n; {
+		t = schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (c *Cronger) location() *time.Location {
+	if c.cfg.Loc == nil {
+		return time.Local
+	}
+	return c.cfg.Loc
+}
@@ -0,0 +1,136 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestPreviewExpression(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Clocks go forward at 02:00 on 31 March 2024.
+	from := time.Date(2024, 3, 29, 12, 0, 0, 0, berlin)
+	times, err := cronger.PreviewExpression("30 2 * * *", berlin, from, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 3, 30, 2, 30, 0, 0, berlin),
+		time.Date(2024, 4, 1, 2, 30, 0, 0, berlin),
+		time.Date(2024, 4, 2, 2, 30, 0, 0, berlin),
+	}, times)
+
+	times, err = cronger.PreviewExpression("@every 90s", time.UTC, from, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{from.Add(90 * time.Second), from.Add(180 * time.Second)}, times)
+
+	// Zones without a name are used as is.
+	fixed := time.FixedZone("UTC+03:00", 3*3600)
+	times, err = cronger.PreviewExpression("0 9 * * *", fixed, time.Date(2024, 1, 1, 0, 0, 0, 0, fixed), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2024, 1, 1, 9, 0, 0, 0, fixed)}, times)
+
+	_, err = cronger.PreviewExpression("* * *", time.UTC, from, 1)
+	assert.ErrorIs(t, err, cronger.ErrInvalidExpression)
+}
+
+func TestNextRuns(t *testing.T) {
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: cronger.NewMemory(),
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 9 * * *",
+		Location:       "Asia/Tokyo",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          2,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+	_, err = cr.NextRuns(uuid.NewString(), 5)
+	assert.ErrorIs(t, err, cronger.ErrJobNotFound)
+
+	times, err := cr.NextRuns(job.Tag, 5)
+	require.NoError(t, err)
+	require.Len(t, times, 2)
+	for _, next := range times {
+		assert.Equal(t, "Asia/Tokyo", next.Location().String())
+		assert.Equal(t, 9, next.Hour())
+		assert.True(t, next.After(time.Now()))
+	}
+	assert.Equal(t, 24*time.Hour, times[1].Sub(times[0]))
+
+	require.NoError(t, cr.Pause(job.Tag))
+	times, err = cr.NextRuns(job.Tag, 5)
+	require.NoError(t, err)
+	assert.Empty(t, times)
+}
+
+func TestNextRunsFixedZone(t *testing.T) {
+	loc := time.FixedZone("UTC+03:00", 3*3600)
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        loc,
+		Repository: cronger.NewMemory(),
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 9 * * *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          3,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+	times, err := cr.NextRuns(job.Tag, 3)
+	require.NoError(t, err)
+	require.Len(t, times, 3)
+	for _, next := range times {
+		assert.Equal(t, loc, next.Location())
+		assert.Equal(t, 9, next.Hour())
+	}
+	assert.Equal(t, 24*time.Hour, times[2].Sub(times[1]))
+}
+
+func TestNextRunsTimezonePrefix(t *testing.T) {
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: cronger.NewMemory(),
+	})
+	require.NoError(t, err)
+	defer cr.Shutdown(context.Background())
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "CRON_TZ=Asia/Tokyo 0 9 * * *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          3,
+	}
+	require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+	times, err := cr.NextRuns(job.Tag, 3)
+	require.NoError(t, err)
+	require.Len(t, times, 3)
+	for _, next := range times {
+		assert.Equal(t, "Asia/Tokyo", next.Location().String())
+		assert.Equal(t, 9, next.Hour())
+	}
+	assert.Equal(t, 24*time.Hour, times[2].Sub(times[1]))
+}
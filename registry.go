@@ -0,0 +1,53 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrHandlerNotFound = errors.New("handler not found")
+	ErrHandlerExists   = errors.New("handler already registered")
+	ErrInvalidHandler  = errors.New("invalid handler")
+)
+
+// Handler runs a job by its function name with the stored function fields.
+type Handler func(ctx context.Context, job Job, fields FunctionFields) error
+
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[string]Handler),
+	}
+}
+
+func (r *Registry) Register(name string, handler Handler) error {
+	if len(name) == 0 || handler == nil {
+		return fmt.Errorf("register %q: %w", name, ErrInvalidHandler)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.handlers[name]; ok {
+		return fmt.Errorf("register %q: %w", name, ErrHandlerExists)
+	}
+	r.handlers[name] = handler
+	return nil
+}
+
+func (r *Registry) Handler(name string) (Handler, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("function %q: %w", name, ErrHandlerNotFound)
+	}
+	return handler, nil
+}
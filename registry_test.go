@@ -0,0 +1,91 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+	"github.com/vladjong/cronger/mocks"
+)
+
+func TestRegistry(t *testing.T) {
+	handler := func(context.Context, cronger.Job, cronger.FunctionFields) error { return nil }
+
+	tests := []struct {
+		name     string
+		register string
+		handler  cronger.Handler
+		lookup   string
+		wantErr  error
+		findErr  error
+	}{
+		{name: "registered", register: "send", handler: handler, lookup: "send"},
+		{name: "duplicate", register: "existing", handler: handler, wantErr: cronger.ErrHandlerExists},
+		{name: "empty name", handler: handler, wantErr: cronger.ErrInvalidHandler},
+		{name: "nil handler", register: "nil", wantErr: cronger.ErrInvalidHandler},
+		{name: "unknown", register: "send", handler: handler, lookup: "unknown", findErr: cronger.ErrHandlerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := cronger.NewRegistry()
+			require.NoError(t, registry.Register("existing", handler))
+
+			err := registry.Register(tt.register, tt.handler)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			found, err := registry.Handler(tt.lookup)
+			if tt.findErr != nil {
+				assert.ErrorIs(t, err, tt.findErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, found)
+		})
+	}
+}
+
+func TestRestoreSuspendedJobs(t *testing.T) {
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "send",
+		FunctionFields: cronger.FunctionFields{"payload"},
+		Limit:          1,
+		Status:         cronger.Suspended,
+	}
+
+	repo := &mocks.Repository{}
+	repo.On("SuspendJobs", mock.Anything).Return([]cronger.Job{job}, nil)
+	// Jobs are polled every minute for the done status.
+	repo.On("Jobs", mock.Anything).Return([]cronger.Job{}, nil).Maybe()
+	repo.On("Add", mock.Anything, mock.MatchedBy(func(in cronger.Job) bool {
+		return in.Tag == job.Tag && in.Status == cronger.Working
+	})).Return(nil)
+
+	registry := cronger.NewRegistry()
+	require.NoError(t, registry.Register("send", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		return nil
+	}))
+
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+		Registry:   registry,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, cr.SuspendJobs())
+	repo.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package cronger
 
 import (
 	"context"
+	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.20.0  --name Repository
@@ -14,4 +15,12 @@ type Repository interface {
 	UpdateStatus(ctx context.Context, tag string, status Status) error
 	SuspendJobs(ctx context.Context) ([]Job, error)
 	SetStatusCancelled(ctx context.Context, ids []string, functionName string) ([]string, error)
+	AddRun(ctx context.Context, in Run) error
+	Runs(ctx context.Context, tag string, filter RunFilter) ([]Run, error)
+	// TryLock claims the occurrence of the job scheduled at the given time,
+	// false means another instance has already claimed it.
+	TryLock(ctx context.Context, tag string, scheduledAt time.Time) (bool, error)
+	// SetDependencies replaces the upstream jobs of the job.
+	SetDependencies(ctx context.Context, tag string, upstream []string) error
+	Dependencies(ctx context.Context) ([]Dependency, error)
 }
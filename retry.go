@@ -0,0 +1,66 @@
+package cronger
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+type Backoff string
+
+const (
+	BackoffFixed       Backoff = "fixed"
+	BackoffExponential Backoff = "exponential"
+)
+
+func (b Backoff) String() string {
+	return string(b)
+}
+
+// RetryDelay returns the delay before the given attempt of a failed run.
+func (j Job) RetryDelay(attempt uint) time.Duration {
+	delay := j.BackoffDelay
+	if j.Backoff == BackoffExponential {
+		for i := uint(1); i < attempt && delay < math.MaxInt64/2; i++ {
+			delay *= 2
+		}
+	}
+	if j.BackoffMax > 0 && delay > j.BackoffMax {
+		delay = j.BackoffMax
+	}
+	if j.Jitter > 0 {
+		delay += time.Duration(rand.Float64() * j.Jitter * float64(delay))
+	}
+	return delay
+}
+
+func (c *Cronger) scheduleRetry(job Job, at time.Time, fnc func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if timer, ok := c.retries[job.Tag]; ok {
+		timer.Stop()
+	}
+	c.retries[job.Tag] = time.AfterFunc(time.Until(at), func() {
+		c.mu.Lock()
+		delete(c.retries, job.Tag)
+		c.mu.Unlock()
+
+		if c.ctx.Err() != nil {
+			return
+		}
+		// Retries belong to the run they repeat.
+		c.fire(job, fnc, at, false)
+	})
+}
+
+func (c *Cronger) stopRetry(tag string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if timer, ok := c.retries[tag]; ok {
+		timer.Stop()
+		delete(c.retries, tag)
+	}
+}
@@ -0,0 +1,80 @@
+package cronger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     cronger.Job
+		attempt uint
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "fixed",
+			job:     cronger.Job{Backoff: cronger.BackoffFixed, BackoffDelay: time.Second},
+			attempt: 5,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "default is fixed",
+			job:     cronger.Job{BackoffDelay: time.Second},
+			attempt: 3,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "exponential first attempt",
+			job:     cronger.Job{Backoff: cronger.BackoffExponential, BackoffDelay: time.Second},
+			attempt: 1,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "exponential",
+			job:     cronger.Job{Backoff: cronger.BackoffExponential, BackoffDelay: time.Second},
+			attempt: 4,
+			min:     8 * time.Second,
+			max:     8 * time.Second,
+		},
+		{
+			name:    "exponential capped by max",
+			job:     cronger.Job{Backoff: cronger.BackoffExponential, BackoffDelay: time.Second, BackoffMax: 5 * time.Second},
+			attempt: 10,
+			min:     5 * time.Second,
+			max:     5 * time.Second,
+		},
+		{
+			name:    "exponential does not overflow",
+			job:     cronger.Job{Backoff: cronger.BackoffExponential, BackoffDelay: time.Second},
+			attempt: 100,
+			min:     time.Second,
+			max:     1<<63 - 1,
+		},
+		{
+			name:    "jitter",
+			job:     cronger.Job{Backoff: cronger.BackoffFixed, BackoffDelay: time.Second, Jitter: 0.5},
+			attempt: 1,
+			min:     time.Second,
+			max:     1500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := tt.job.RetryDelay(tt.attempt)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
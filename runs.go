@@ -0,0 +1,61 @@
+package cronger
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	_runsLimit = 100
+)
+
+// Run is a single execution of a job.
+type Run struct {
+	ID         int64         `db:"id" goqu:"skipinsert"`
+	Tag        string        `db:"tag"`
+	StartedAt  time.Time     `db:"started_at"`
+	FinishedAt time.Time     `db:"finished_at"`
+	Duration   time.Duration `db:"duration"`
+	// Outcome of the run: done, failed, cancelled, suspended by Shutdown
+	// or skipped by the overlap policy.
+	Status  Status `db:"status"`
+	Error   string `db:"error"`
+	Attempt uint   `db:"attempt"`
+	// Instance that executed the run.
+	Node string `db:"node"`
+}
+
+type RunFilter struct {
+	Status Status
+	// Range of the run start time.
+	From time.Time
+	To   time.Time
+	// Page size, zero means 100.
+	Limit  uint
+	Offset uint
+}
+
+func (c *Cronger) Runs(tag string, filter RunFilter) ([]Run, error) {
+	if filter.Limit == 0 {
+		filter.Limit = _runsLimit
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	runs, err := c.cfg.Repository.Runs(ctx, tag, filter)
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (c *Cronger) addRun(run Run) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _timeOut)
+	defer cancel()
+
+	if err := c.cfg.Repository.AddRun(ctx, run); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cronger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+	"github.com/vladjong/cronger/mocks"
+)
+
+func TestRuns(t *testing.T) {
+	tag := uuid.NewString()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter cronger.RunFilter
+		want   cronger.RunFilter
+	}{
+		{name: "default limit", want: cronger.RunFilter{Limit: 100}},
+		{name: "page", filter: cronger.RunFilter{Limit: 10, Offset: 20}, want: cronger.RunFilter{Limit: 10, Offset: 20}},
+		{name: "offset", filter: cronger.RunFilter{Offset: 200}, want: cronger.RunFilter{Limit: 100, Offset: 200}},
+		{
+			name:   "status and range",
+			filter: cronger.RunFilter{Status: cronger.Failed, From: start, To: start.Add(time.Hour)},
+			want:   cronger.RunFilter{Status: cronger.Failed, From: start, To: start.Add(time.Hour), Limit: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := []cronger.Run{{ID: 1, Tag: tag, StartedAt: start, Status: cronger.Done}}
+
+			repo := &mocks.Repository{}
+			repo.On("SuspendJobs", mock.Anything).Return([]cronger.Job{}, nil)
+			// Jobs are polled every minute for the done status.
+			repo.On("Jobs", mock.Anything).Return([]cronger.Job{}, nil).Maybe()
+			repo.On("Runs", mock.Anything, tag, tt.want).Return(runs, nil)
+
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+
+			got, err := cr.Runs(tag, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, runs, got)
+		})
+	}
+}
+
+func TestRunRecorded(t *testing.T) {
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Expression:     "0 0 1 1 *",
+		FunctionName:   "test",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          1,
+	}
+
+	recorded := make(chan cronger.Run, 1)
+	repo := &mocks.Repository{}
+	repo.On("SuspendJobs", mock.Anything).Return([]cronger.Job{}, nil)
+	// Jobs are polled every minute for the done status.
+	repo.On("Jobs", mock.Anything).Return([]cronger.Job{}, nil).Maybe()
+	repo.On("Update", mock.Anything, job.Tag, mock.Anything).Return(nil)
+	repo.On("AddRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded <- args.Get(1).(cronger.Run)
+	}).Return(nil)
+
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+
+	cr.Template(job, func(context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return errors.New("boom")
+	})
+
+	select {
+	case run := <-recorded:
+		assert.Equal(t, job.Tag, run.Tag)
+		assert.Equal(t, cronger.Failed, run.Status)
+		assert.Equal(t, "boom", run.Error)
+		assert.GreaterOrEqual(t, run.Duration, 10*time.Millisecond)
+		assert.False(t, run.FinishedAt.Before(run.StartedAt))
+	case <-time.After(time.Second):
+		t.Fatal("run is not recorded")
+	}
+}
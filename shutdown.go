@@ -0,0 +1,101 @@
+package cronger
+
+import (
+	"context"
+	"errors"
+)
+
+// Shutdown stops new firings and waits for the running tasks until ctx is done,
+// the rest are cancelled. Queued runs are dropped. Working and retrying jobs of the instance are marked
+// as suspended, so the next New resumes them.
+func (c *Cronger) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrShutdown
+	}
+	c.closed = true
+	for tag, timer := range c.retries {
+		timer.Stop()
+		delete(c.retries, tag)
+	}
+	c.mu.Unlock()
+
+	if c.pool != nil {
+		c.pool.close()
+	}
+
+	// Stop waits for the running gocron jobs, the deadline is handled below.
+	// Clear removes the jobs under the scheduler lock first, Stop reads them
+	// without it.
+	stopped := make(chan struct{})
+	go func() {
+		c.schedule.Clear()
+		c.schedule.Stop()
+		close(stopped)
+	}()
+
+	drained := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(drained)
+	}()
+
+	var errs []error
+	select {
+	case <-drained:
+		<-stopped
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+		c.mu.Lock()
+		for _, runs := range c.running {
+			for _, cancel := range runs {
+				cancel(ErrShutdown)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	if err := c.suspendScheduled(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.hooks.close(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cronger) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *Cronger) suspendScheduled() error {
+	c.mu.Lock()
+	jobs := make([]Job, 0, len(c.scheduled))
+	for _, fields := range c.scheduled {
+		if fields.Status == Working || fields.Status == Retrying {
+			// The restored job gets the occurrences left of its limit.
+			fields.FireCount = c.fired[fields.Tag]
+			jobs = append(jobs, fields.Job)
+		}
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, job := range jobs {
+		err := c.update(job.Tag, map[string]interface{}{
+			_status:    Suspended.String(),
+			_fireCount: job.FireCount,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			c.logger.Error("suspend job", jobAttrs(job, _error, err)...)
+			continue
+		}
+		c.changeStatus(job, Suspended)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,187 @@
+package cronger_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+func TestShutdown(t *testing.T) {
+	repo := cronger.NewMemory()
+	cr, err := cronger.New(&cronger.Config{
+		Loc:        time.UTC,
+		Repository: repo,
+	})
+	require.NoError(t, err)
+
+	newJob := func() cronger.Job {
+		return cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			Expression:     "0 0 1 1 *",
+			FunctionName:   "test",
+			FunctionFields: cronger.FunctionFields{},
+			Limit:          1,
+		}
+	}
+	task := func(context.Context) error { return nil }
+	drained, interrupted := newJob(), newJob()
+	require.NoError(t, cr.Add(cronger.Fields{Job: drained, Task: task}))
+	require.NoError(t, cr.Add(cronger.Fields{Job: interrupted, Task: task}))
+
+	started := make(chan struct{}, 2)
+	go cr.Template(drained, func(context.Context) error {
+		started <- struct{}{}
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	go cr.Template(interrupted, func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, cr.Shutdown(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, cr.Shutdown(ctx), cronger.ErrShutdown)
+	assert.ErrorIs(t, cr.Add(cronger.Fields{Job: newJob(), Task: task}), cronger.ErrShutdown)
+
+	jobs, err := repo.Jobs(context.Background())
+	require.NoError(t, err)
+	status := make(map[string]cronger.Status, len(jobs))
+	for _, job := range jobs {
+		status[job.Tag] = job.Status
+	}
+	assert.Equal(t, cronger.Done, status[drained.Tag])
+	assert.Equal(t, cronger.Suspended, status[interrupted.Tag])
+
+	assert.Eventually(t, func() bool {
+		runs, err := repo.Runs(context.Background(), interrupted.Tag, cronger.RunFilter{})
+		return err == nil && len(runs) == 1 && runs[0].Status == cronger.Suspended
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestShutdownRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	registry := cronger.NewRegistry()
+	var runs atomic.Int32
+	require.NoError(t, registry.Register("restore", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		runs.Add(1)
+		return nil
+	}))
+	config := func() *cronger.Config {
+		return &cronger.Config{
+			Loc:        time.UTC,
+			Repository: repo,
+			Registry:   registry,
+		}
+	}
+
+	cr, err := cronger.New(config())
+	require.NoError(t, err)
+
+	newJob := func(limit uint) cronger.Job {
+		return cronger.Job{
+			Tag:            uuid.NewString(),
+			ID:             uuid.NewString(),
+			Expression:     "* * * * * *",
+			FunctionName:   "restore",
+			FunctionFields: cronger.FunctionFields{},
+			Limit:          limit,
+		}
+	}
+	recurring, finished := newJob(50), newJob(1)
+	require.NoError(t, cr.AddJob(recurring))
+	require.NoError(t, cr.AddJob(finished))
+
+	status := func() map[string]cronger.Status {
+		jobs, err := repo.Jobs(ctx)
+		require.NoError(t, err)
+		status := make(map[string]cronger.Status, len(jobs))
+		for _, job := range jobs {
+			status[job.Tag] = job.Status
+		}
+		return status
+	}
+	require.Eventually(t, func() bool {
+		history, err := repo.Runs(ctx, recurring.Tag, cronger.RunFilter{})
+		return err == nil && len(history) != 0 && status()[finished.Tag] == cronger.Done
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, cronger.Working, status()[recurring.Tag])
+
+	require.NoError(t, cr.Shutdown(ctx))
+	assert.Equal(t, cronger.Suspended, status()[recurring.Tag])
+	assert.Equal(t, cronger.Done, status()[finished.Tag])
+
+	before := runs.Load()
+	cr, err = cronger.New(config())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	assert.Equal(t, cronger.Working, status()[recurring.Tag])
+	assert.Equal(t, cronger.Done, status()[finished.Tag])
+	assert.Eventually(t, func() bool { return runs.Load() > before }, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestShutdownRestoreLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := cronger.NewMemory()
+	registry := cronger.NewRegistry()
+	var runs atomic.Int32
+	require.NoError(t, registry.Register("limited", func(context.Context, cronger.Job, cronger.FunctionFields) error {
+		runs.Add(1)
+		return nil
+	}))
+	config := func() *cronger.Config {
+		return &cronger.Config{
+			Loc:        time.UTC,
+			Repository: repo,
+			Registry:   registry,
+		}
+	}
+
+	cr, err := cronger.New(config())
+	require.NoError(t, err)
+
+	job := cronger.Job{
+		Tag:            uuid.NewString(),
+		ID:             uuid.NewString(),
+		Interval:       100 * time.Millisecond,
+		FunctionName:   "limited",
+		FunctionFields: cronger.FunctionFields{},
+		Limit:          5,
+	}
+	require.NoError(t, cr.AddJob(job))
+	stored := func() cronger.Job {
+		jobs, err := repo.Jobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		return jobs[0]
+	}
+
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, 3*time.Second, 10*time.Millisecond)
+	require.NoError(t, cr.Shutdown(ctx))
+	require.Equal(t, cronger.Suspended, stored().Status)
+	require.Less(t, runs.Load(), int32(job.Limit))
+	assert.Equal(t, uint(runs.Load()), stored().FireCount)
+
+	cr, err = cronger.New(config())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cr.Shutdown(context.Background()) })
+
+	require.Eventually(t, func() bool { return stored().Status == cronger.Done }, 3*time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(job.Limit), runs.Load())
+	assert.Equal(t, job.Limit, stored().FireCount)
+}
@@ -0,0 +1,167 @@
+package cronger
+
+import (
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const (
+	_statusType       = "CRONJOB_STATUS"
+	_uniqueConstraint = "unique_title_operation"
+)
+
+type SqlxOption func(*sqlxOptions)
+
+// WithSchema sets the schema of the tables and the status type.
+func WithSchema(schema string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.schema = schema
+	}
+}
+
+// WithTable sets the jobs table, the other tables are named after it
+// unless they are set explicitly.
+func WithTable(table string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.table = table
+	}
+}
+
+func WithRunsTable(table string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.runsTable = table
+	}
+}
+
+func WithDependenciesTable(table string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.dependenciesTable = table
+	}
+}
+
+func WithMigrationsTable(table string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.migrationsTable = table
+	}
+}
+
+func WithStatusType(name string) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.statusType = name
+	}
+}
+
+// WithStatementCache keeps prepared statements on the *sqlx.DB for reuse,
+// SqlxRepository.Close releases them.
+func WithStatementCache() SqlxOption {
+	return func(o *sqlxOptions) {
+		o.statementCache = true
+	}
+}
+
+// WithLogger sets the logger of the errors that are not returned to the caller,
+// the standard log package by default.
+func WithLogger(logger Logger) SqlxOption {
+	return func(o *sqlxOptions) {
+		o.logger = logger
+	}
+}
+
+type sqlxOptions struct {
+	schema            string
+	table             string
+	runsTable         string
+	dependenciesTable string
+	migrationsTable   string
+	statusType        string
+	statementCache    bool
+	logger            Logger
+}
+
+func newSqlxOptions(opts ...SqlxOption) sqlxOptions {
+	o := sqlxOptions{
+		table:      _jobsTable,
+		statusType: _statusType,
+		logger:     defaultLogger(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = defaultLogger()
+	}
+
+	if len(o.runsTable) == 0 {
+		o.runsTable = _jobRunsTable
+		if o.table != _jobsTable {
+			o.runsTable = o.table + "_runs"
+		}
+	}
+	if len(o.dependenciesTable) == 0 {
+		o.dependenciesTable = _jobDependenciesTable
+		if o.table != _jobsTable {
+			o.dependenciesTable = o.table + "_dependencies"
+		}
+	}
+	if len(o.migrationsTable) == 0 {
+		o.migrationsTable = _migrationsTable
+		if o.table != _jobsTable {
+			o.migrationsTable = o.table + "_migrations"
+		}
+	}
+	return o
+}
+
+func (o sqlxOptions) jobs() exp.IdentifierExpression {
+	return o.identifier(o.table)
+}
+
+func (o sqlxOptions) runs() exp.IdentifierExpression {
+	return o.identifier(o.runsTable)
+}
+
+func (o sqlxOptions) dependencies() exp.IdentifierExpression {
+	return o.identifier(o.dependenciesTable)
+}
+
+func (o sqlxOptions) migrations() exp.IdentifierExpression {
+	return o.identifier(o.migrationsTable)
+}
+
+func (o sqlxOptions) identifier(table string) exp.IdentifierExpression {
+	if len(o.schema) == 0 {
+		return goqu.T(table)
+	}
+	return goqu.S(o.schema).Table(table)
+}
+
+// quote returns the quoted identifier qualified with the schema for raw SQL.
+func (o sqlxOptions) quote(name string) string {
+	if len(o.schema) == 0 {
+		return quoteIdent(name)
+	}
+	return quoteIdent(o.schema) + "." + quoteIdent(name)
+}
+
+// templateData returns the identifiers used by the migration templates.
+func (o sqlxOptions) templateData() map[string]string {
+	unique := _uniqueConstraint
+	if o.table != _jobsTable {
+		unique = o.table + "_" + _uniqueConstraint
+	}
+
+	return map[string]string{
+		"Jobs":         o.quote(o.table),
+		"Runs":         o.quote(o.runsTable),
+		"Dependencies": o.quote(o.dependenciesTable),
+		"StatusType":   o.quote(o.statusType),
+		"UniqueJob":    quoteIdent(unique),
+		"RunsIndex":    quoteIdent(o.runsTable + "_tag_started_at"),
+	}
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
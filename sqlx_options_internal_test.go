@@ -0,0 +1,93 @@
+package cronger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSqlxOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []SqlxOption
+		table        string
+		runs         string
+		dependencies string
+		migrations   string
+		statusType   string
+	}{
+		{
+			name:         "default",
+			table:        "jobs",
+			runs:         "job_runs",
+			dependencies: "job_dependencies",
+			migrations:   "cronger_migrations",
+			statusType:   "CRONJOB_STATUS",
+		},
+		{
+			name:         "derived from table",
+			opts:         []SqlxOption{WithTable("billing")},
+			table:        "billing",
+			runs:         "billing_runs",
+			dependencies: "billing_dependencies",
+			migrations:   "billing_migrations",
+			statusType:   "CRONJOB_STATUS",
+		},
+		{
+			name: "explicit",
+			opts: []SqlxOption{
+				WithTable("billing"),
+				WithRunsTable("history"),
+				WithDependenciesTable("edges"),
+				WithMigrationsTable("versions"),
+				WithStatusType("BILLING_STATUS"),
+			},
+			table:        "billing",
+			runs:         "history",
+			dependencies: "edges",
+			migrations:   "versions",
+			statusType:   "BILLING_STATUS",
+		},
+		{
+			name:         "nil logger",
+			opts:         []SqlxOption{WithLogger(nil)},
+			table:        "jobs",
+			runs:         "job_runs",
+			dependencies: "job_dependencies",
+			migrations:   "cronger_migrations",
+			statusType:   "CRONJOB_STATUS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newSqlxOptions(tt.opts...)
+			assert.Equal(t, tt.table, o.table)
+			assert.Equal(t, tt.runs, o.runsTable)
+			assert.Equal(t, tt.dependencies, o.dependenciesTable)
+			assert.Equal(t, tt.migrations, o.migrationsTable)
+			assert.Equal(t, tt.statusType, o.statusType)
+			assert.NotNil(t, o.logger)
+		})
+	}
+}
+
+func TestSqlxOptionsQuote(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		ident  string
+		want   string
+	}{
+		{name: "table", ident: "jobs", want: `"jobs"`},
+		{name: "schema", schema: "scheduler", ident: "jobs", want: `"scheduler"."jobs"`},
+		{name: "escaped quote", schema: `a"b`, ident: `c"d`, want: `"a""b"."c""d"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newSqlxOptions(WithSchema(tt.schema))
+			assert.Equal(t, tt.want, o.quote(tt.ident))
+		})
+	}
+}
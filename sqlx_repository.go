@@ -2,45 +2,72 @@ package cronger
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
-	_jobsTable    = "jobs"
-	_tag          = "tag"
-	_status       = "status"
-	_functionName = "function_name"
-	_id           = "id"
+	_jobsTable            = "jobs"
+	_jobRunsTable         = "job_runs"
+	_jobDependenciesTable = "job_dependencies"
+	_tag                  = "tag"
+	_status               = "status"
+	_statusDescription    = "status_description"
+	_functionName         = "function_name"
+	_id                   = "id"
+	_attempt              = "attempt"
+	_nextAttemptAt        = "next_attempt_at"
+	_startedAt            = "started_at"
+	_firedAt              = "fired_at"
+	_fireCount            = "fire_count"
+	_upstream             = "upstream"
 )
 
+var _dialect = goqu.Dialect("postgres")
+
 type SqlxRepository struct {
-	db *sqlx.DB
+	db   *sqlx.DB
+	opts sqlxOptions
+
+	mu    sync.Mutex
+	stmts map[string]*sqlx.Stmt
 }
 
-func NewSqlx(db *sqlx.DB) *SqlxRepository {
-	return &SqlxRepository{
-		db: db,
+func NewSqlx(db *sqlx.DB, opts ...SqlxOption) *SqlxRepository {
+	r := &SqlxRepository{
+		db:   db,
+		opts: newSqlxOptions(opts...),
 	}
+	if r.opts.statementCache {
+		r.stmts = make(map[string]*sqlx.Stmt)
+	}
+	return r
 }
 
 func (r *SqlxRepository) Jobs(ctx context.Context) ([]Job, error) {
-	query, _, err := goqu.From(_jobsTable).ToSQL()
+	query, args, err := r.from(r.opts.jobs()).ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("configure query: %w", err)
 	}
 
 	var jobs []Job
-	if err := r.db.SelectContext(ctx, &jobs, query); err != nil {
+	if err := r.selectContext(ctx, nil, &jobs, query, args); err != nil {
 		return nil, fmt.Errorf("select jobs: %w", err)
 	}
 	return jobs, nil
 }
 
 func (r *SqlxRepository) JobsByStatus(ctx context.Context, status Status) ([]Job, error) {
-	query, _, err := goqu.From(_jobsTable).
+	query, args, err := r.from(r.opts.jobs()).
 		Where(goqu.C(_status).Eq(status.String())).
 		ToSQL()
 	if err != nil {
@@ -48,14 +75,14 @@ func (r *SqlxRepository) JobsByStatus(ctx context.Context, status Status) ([]Job
 	}
 
 	var jobs []Job
-	if err := r.db.SelectContext(ctx, &jobs, query); err != nil {
+	if err := r.selectContext(ctx, nil, &jobs, query, args); err != nil {
 		return nil, fmt.Errorf("select jobs by statust=%s: %w", status.String(), err)
 	}
 	return jobs, nil
 }
 
 func (r *SqlxRepository) Add(ctx context.Context, in Job) error {
-	query, _, err := goqu.Insert(_jobsTable).
+	query, args, err := r.insert(r.opts.jobs()).
 		Rows(in).
 		OnConflict(goqu.DoUpdate(_tag, in)).
 		ToSQL()
@@ -63,23 +90,21 @@ func (r *SqlxRepository) Add(ctx context.Context, in Job) error {
 		return fmt.Errorf("configure query: %w", err)
 	}
 
-	if _, err := r.db.ExecContext(ctx, query); err != nil {
+	if _, err := r.execContext(ctx, nil, query, args); err != nil {
 		return fmt.Errorf("insert job: %w", err)
 	}
 	return nil
 }
 
 func (r *SqlxRepository) SuspendJobs(ctx context.Context) ([]Job, error) {
-	tx, err := r.db.Beginx()
-	defer func() {
-		_ = tx.Rollback()
-	}()
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("begin: %w", err)
 	}
+	defer r.rollback(tx)
 
-	queryUpdate, _, err := goqu.Update(_jobsTable).
-		Where(goqu.C(_status).Eq(Working)).
+	queryUpdate, argsUpdate, err := r.update(r.opts.jobs()).
+		Where(goqu.C(_status).In(Working.String(), Retrying.String())).
 		Set(goqu.Record{
 			_status: Suspended.String(),
 		}).ToSQL()
@@ -87,18 +112,18 @@ func (r *SqlxRepository) SuspendJobs(ctx context.Context) ([]Job, error) {
 		return nil, fmt.Errorf("configure query: %w", err)
 	}
 
-	if _, err := tx.ExecContext(ctx, queryUpdate); err != nil {
+	if _, err := r.execContext(ctx, tx, queryUpdate, argsUpdate); err != nil {
 		return nil, fmt.Errorf("update jobs: %w", err)
 	}
 
-	query, _, err := goqu.From(_jobsTable).
-		Where(goqu.C(_status).Eq(Suspended)).ToSQL()
+	query, args, err := r.from(r.opts.jobs()).
+		Where(goqu.C(_status).Eq(Suspended.String())).ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("configure query: %w", err)
 	}
 
 	var jobs []Job
-	if err := tx.SelectContext(ctx, &jobs, query); err != nil {
+	if err := r.selectContext(ctx, tx, &jobs, query, args); err != nil {
 		return nil, fmt.Errorf("select jobs: %w", err)
 	}
 
@@ -109,26 +134,25 @@ func (r *SqlxRepository) SuspendJobs(ctx context.Context) ([]Job, error) {
 }
 
 func (r *SqlxRepository) SetStatusCancelled(ctx context.Context, ids []string, functionName string) ([]string, error) {
-	tx, err := r.db.Beginx()
-	defer func() {
-		_ = tx.Rollback()
-	}()
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("begin: %w", err)
 	}
+	defer r.rollback(tx)
 
-	getQuery, _, err := goqu.From(_jobsTable).
+	// ANY keeps the query text the same for any number of ids.
+	getQuery, getArgs, err := r.from(r.opts.jobs()).
 		Where(
-			goqu.C(_status).Eq(Working),
+			goqu.C(_status).Eq(Working.String()),
 			goqu.C(_functionName).Eq(functionName),
-			goqu.C(_id).In(ids),
+			goqu.L("? = ANY(?)", goqu.C(_id), pq.Array(ids)),
 		).ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("configure query: %w", err)
 	}
 
 	var jobs []Job
-	if err := tx.SelectContext(ctx, &jobs, getQuery); err != nil {
+	if err := r.selectContext(ctx, tx, &jobs, getQuery, getArgs); err != nil {
 		return nil, fmt.Errorf("select jobs: %w", err)
 	}
 
@@ -137,8 +161,8 @@ func (r *SqlxRepository) SetStatusCancelled(ctx context.Context, ids []string, f
 		tags[i] = job.Tag
 	}
 
-	updateQuery, _, err := goqu.Update(_jobsTable).
-		Where(goqu.C("tag").In(tags)).
+	updateQuery, updateArgs, err := r.update(r.opts.jobs()).
+		Where(goqu.L("? = ANY(?)", goqu.C(_tag), pq.Array(tags))).
 		Set(goqu.Record{
 			_status: Cancelled.String(),
 		}).
@@ -147,7 +171,7 @@ func (r *SqlxRepository) SetStatusCancelled(ctx context.Context, ids []string, f
 		return nil, fmt.Errorf("configure query: %w", err)
 	}
 
-	if _, err := tx.ExecContext(ctx, updateQuery); err != nil {
+	if _, err := r.execContext(ctx, tx, updateQuery, updateArgs); err != nil {
 		return nil, fmt.Errorf("update jobs: %w", err)
 	}
 
@@ -158,33 +182,33 @@ func (r *SqlxRepository) SetStatusCancelled(ctx context.Context, ids []string, f
 }
 
 func (r *SqlxRepository) Remove(ctx context.Context, tag string) error {
-	query, _, err := goqu.Delete(_jobsTable).
+	query, args, err := r.delete(r.opts.jobs()).
 		Where(goqu.C(_tag).Eq(tag)).
 		Returning("tag").ToSQL()
 	if err != nil {
 		return fmt.Errorf("configure query: %w", err)
 	}
 
-	if _, err := r.db.ExecContext(ctx, query); err != nil {
+	if _, err := r.execContext(ctx, nil, query, args); err != nil {
 		return fmt.Errorf("delete job = %s: %w", tag, err)
 	}
 	return nil
 }
 
 func (r *SqlxRepository) Update(ctx context.Context, tag string, in map[string]interface{}) error {
-	updateQuery, _, err := goqu.Update(_jobsTable).
+	updateQuery, args, err := r.update(r.opts.jobs()).
 		Where(goqu.C("tag").Eq(tag)).Set(in).ToSQL()
 	if err != nil {
 		return fmt.Errorf("configure query: %w", err)
 	}
-	if _, err := r.db.ExecContext(ctx, updateQuery); err != nil {
+	if _, err := r.execContext(ctx, nil, updateQuery, args); err != nil {
 		return fmt.Errorf("update: %w", err)
 	}
 	return nil
 }
 
 func (r *SqlxRepository) UpdateStatus(ctx context.Context, tag string, status Status) error {
-	updateQuery, _, err := goqu.Update(_jobsTable).
+	updateQuery, args, err := r.update(r.opts.jobs()).
 		Where(goqu.C("tag").Eq(tag)).
 		Set(goqu.Record{
 			_status: status.String(),
@@ -193,8 +217,281 @@ func (r *SqlxRepository) UpdateStatus(ctx context.Context, tag string, status St
 		return fmt.Errorf("configure query: %w", err)
 	}
 
-	if _, err := r.db.ExecContext(ctx, updateQuery); err != nil {
+	if _, err := r.execContext(ctx, nil, updateQuery, args); err != nil {
 		return fmt.Errorf("update status: %w", err)
 	}
 	return nil
 }
+
+func (r *SqlxRepository) AddRun(ctx context.Context, in Run) error {
+	query, args, err := r.insert(r.opts.runs()).Rows(in).ToSQL()
+	if err != nil {
+		return fmt.Errorf("configure query: %w", err)
+	}
+
+	if _, err := r.execContext(ctx, nil, query, args); err != nil {
+		return fmt.Errorf("insert run: %w", err)
+	}
+	return nil
+}
+
+func (r *SqlxRepository) Runs(ctx context.Context, tag string, filter RunFilter) ([]Run, error) {
+	ds := r.from(r.opts.runs()).Where(goqu.C(_tag).Eq(tag))
+	if len(filter.Status) != 0 {
+		ds = ds.Where(goqu.C(_status).Eq(filter.Status.String()))
+	}
+	if !filter.From.IsZero() {
+		ds = ds.Where(goqu.C(_startedAt).Gte(filter.From))
+	}
+	if !filter.To.IsZero() {
+		ds = ds.Where(goqu.C(_startedAt).Lt(filter.To))
+	}
+
+	query, args, err := ds.
+		Order(goqu.C(_startedAt).Desc(), goqu.C(_id).Desc()).
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("configure query: %w", err)
+	}
+
+	var runs []Run
+	if err := r.selectContext(ctx, nil, &runs, query, args); err != nil {
+		return nil, fmt.Errorf("select runs tag=%s: %w", tag, err)
+	}
+	return runs, nil
+}
+
+func (r *SqlxRepository) TryLock(ctx context.Context, tag string, scheduledAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin: %w", err)
+	}
+	defer r.rollback(tx)
+
+	key := fmt.Sprintf("%s/%s/%d", r.opts.quote(r.opts.table), tag, scheduledAt.UnixMicro())
+	lockQuery, lockArgs, err := _dialect.Select(
+		goqu.Func("pg_try_advisory_xact_lock", goqu.Func("hashtextextended", key, 0)),
+	).Prepared(true).ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("configure query: %w", err)
+	}
+
+	var locked bool
+	if err := r.getContext(ctx, tx, &locked, lockQuery, lockArgs); err != nil {
+		return false, fmt.Errorf("lock job=%s: %w", tag, err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	updateQuery, updateArgs, err := r.update(r.opts.jobs()).
+		Where(
+			goqu.C(_tag).Eq(tag),
+			goqu.Or(
+				goqu.C(_firedAt).IsNull(),
+				goqu.C(_firedAt).Neq(scheduledAt),
+			),
+		).
+		Set(goqu.Record{
+			_firedAt: scheduledAt,
+		}).ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("configure query: %w", err)
+	}
+
+	result, err := r.execContext(ctx, tx, updateQuery, updateArgs)
+	if err != nil {
+		return false, fmt.Errorf("update fired_at: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("update fired_at: %w", err)
+	}
+	if claimed == 0 {
+		return false, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *SqlxRepository) SetDependencies(ctx context.Context, tag string, upstream []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer r.rollback(tx)
+
+	deleteQuery, deleteArgs, err := r.delete(r.opts.dependencies()).
+		Where(goqu.C(_tag).Eq(tag)).ToSQL()
+	if err != nil {
+		return fmt.Errorf("configure query: %w", err)
+	}
+
+	if _, err := r.execContext(ctx, tx, deleteQuery, deleteArgs); err != nil {
+		return fmt.Errorf("delete dependencies tag=%s: %w", tag, err)
+	}
+
+	if len(upstream) != 0 {
+		rows := make([]Dependency, len(upstream))
+		for i, up := range upstream {
+			rows[i] = Dependency{Tag: tag, Upstream: up}
+		}
+
+		insertQuery, insertArgs, err := r.insert(r.opts.dependencies()).
+			Rows(rows).
+			OnConflict(goqu.DoNothing()).
+			ToSQL()
+		if err != nil {
+			return fmt.Errorf("configure query: %w", err)
+		}
+
+		if _, err := r.execContext(ctx, tx, insertQuery, insertArgs); err != nil {
+			return fmt.Errorf("insert dependencies tag=%s: %w", tag, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SqlxRepository) Dependencies(ctx context.Context) ([]Dependency, error) {
+	query, args, err := r.from(r.opts.dependencies()).
+		Order(goqu.C(_tag).Asc(), goqu.C(_upstream).Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("configure query: %w", err)
+	}
+
+	var dependencies []Dependency
+	if err := r.selectContext(ctx, nil, &dependencies, query, args); err != nil {
+		return nil, fmt.Errorf("select dependencies: %w", err)
+	}
+	return dependencies, nil
+}
+
+// Close releases the cached prepared statements.
+func (r *SqlxRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for query, stmt := range r.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(r.stmts, query)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *SqlxRepository) from(table exp.IdentifierExpression) *goqu.SelectDataset {
+	return _dialect.From(table).Prepared(true)
+}
+
+func (r *SqlxRepository) insert(table exp.IdentifierExpression) *goqu.InsertDataset {
+	return _dialect.Insert(table).Prepared(true)
+}
+
+func (r *SqlxRepository) update(table exp.IdentifierExpression) *goqu.UpdateDataset {
+	return _dialect.Update(table).Prepared(true)
+}
+
+func (r *SqlxRepository) delete(table exp.IdentifierExpression) *goqu.DeleteDataset {
+	return _dialect.Delete(table).Prepared(true)
+}
+
+func (r *SqlxRepository) execContext(ctx context.Context, tx *sqlx.Tx, query string, args []interface{}) (sql.Result, error) {
+	stmt, err := r.prepare(ctx, tx, query)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case stmt != nil:
+		return stmt.ExecContext(ctx, args...)
+	case tx != nil:
+		return tx.ExecContext(ctx, query, args...)
+	default:
+		return r.db.ExecContext(ctx, query, args...)
+	}
+}
+
+func (r *SqlxRepository) selectContext(ctx context.Context, tx *sqlx.Tx, dest interface{}, query string, args []interface{}) error {
+	stmt, err := r.prepare(ctx, tx, query)
+	if err != nil {
+		return err
+	}
+	switch {
+	case stmt != nil:
+		return stmt.SelectContext(ctx, dest, args...)
+	case tx != nil:
+		return tx.SelectContext(ctx, dest, query, args...)
+	default:
+		return r.db.SelectContext(ctx, dest, query, args...)
+	}
+}
+
+func (r *SqlxRepository) getContext(ctx context.Context, tx *sqlx.Tx, dest interface{}, query string, args []interface{}) error {
+	stmt, err := r.prepare(ctx, tx, query)
+	if err != nil {
+		return err
+	}
+	switch {
+	case stmt != nil:
+		return stmt.GetContext(ctx, dest, args...)
+	case tx != nil:
+		return tx.GetContext(ctx, dest, query, args...)
+	default:
+		return r.db.GetContext(ctx, dest, query, args...)
+	}
+}
+
+// rollback ends the transaction unless it is already committed.
+func (r *SqlxRepository) rollback(tx *sqlx.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		r.opts.logger.Error("rollback", _error, err)
+	}
+}
+
+func (r *SqlxRepository) closeStmt(stmt *sqlx.Stmt) {
+	if err := stmt.Close(); err != nil {
+		r.opts.logger.Error("close statement", _error, err)
+	}
+}
+
+// prepare returns the cached statement for the query, nil when the cache is disabled.
+func (r *SqlxRepository) prepare(ctx context.Context, tx *sqlx.Tx, query string) (*sqlx.Stmt, error) {
+	if r.stmts == nil {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	stmt, ok := r.stmts[query]
+	r.mu.Unlock()
+	if !ok {
+		prepared, err := r.db.PreparexContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("prepare: %w", err)
+		}
+
+		r.mu.Lock()
+		if stmt, ok = r.stmts[query]; ok {
+			r.closeStmt(prepared)
+		} else {
+			stmt = prepared
+			r.stmts[query] = stmt
+		}
+		r.mu.Unlock()
+	}
+
+	if tx != nil {
+		return tx.StmtxContext(ctx, stmt), nil
+	}
+	return stmt, nil
+}
@@ -0,0 +1,413 @@
+package cronger_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+)
+
+const (
+	_tag          = "0b0c7d8e-6f3a-4c1e-9a53-3c8b0f7e2d11"
+	_id           = "5d2f1a9c-8b7e-4f60-a1d2-7e9c3b4a5f60"
+	_functionName = "send'; DROP TABLE jobs; --"
+	_payload      = "payload-secret"
+	_description  = "failed with 'quote'"
+)
+
+// noInterpolation fails when user data is found in the SQL text.
+func noInterpolation(values ...string) sqlmock.QueryMatcher {
+	return sqlmock.QueryMatcherFunc(func(_, actualSQL string) error {
+		for _, value := range values {
+			if strings.Contains(actualSQL, value) {
+				return fmt.Errorf("%q is interpolated into %q", value, actualSQL)
+			}
+		}
+		if !strings.Contains(actualSQL, "$1") {
+			return fmt.Errorf("no bound arguments in %q", actualSQL)
+		}
+		return nil
+	})
+}
+
+func newMockRepository(t *testing.T, opts ...cronger.SqlxOption) (*cronger.SqlxRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(
+		noInterpolation(_tag, _id, _functionName, _payload, _description),
+	))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	return cronger.NewSqlx(sqlx.NewDb(db, "postgres"), opts...), mock
+}
+
+func TestSqlxRepositoryBindsArguments(t *testing.T) {
+	ctx := context.Background()
+	job := cronger.Job{
+		Tag:            _tag,
+		ID:             _id,
+		Expression:     "* * * * *",
+		FunctionName:   _functionName,
+		FunctionFields: cronger.FunctionFields{map[string]string{"key": _payload}},
+		Limit:          1,
+		Status:         cronger.Working,
+	}
+	columns := []string{"tag", "id", "function_name"}
+
+	tests := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		call func(repo *cronger.SqlxRepository) error
+	}{
+		{
+			name: "add",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("insert").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.Add(ctx, job)
+			},
+		},
+		{
+			name: "jobs_by_status",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("select").
+					WithArgs(cronger.Failed.String()).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				_, err := repo.JobsByStatus(ctx, cronger.Failed)
+				return err
+			},
+		},
+		{
+			name: "remove",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("delete").WithArgs(_tag).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.Remove(ctx, _tag)
+			},
+		},
+		{
+			name: "update",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("update").
+					WithArgs(_description, _tag).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.Update(ctx, _tag, map[string]interface{}{"status_description": _description})
+			},
+		},
+		{
+			name: "update_status",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("update").
+					WithArgs(cronger.Done.String(), _tag).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.UpdateStatus(ctx, _tag, cronger.Done)
+			},
+		},
+		{
+			name: "set_status_cancelled",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("select").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(_tag, _id, _functionName))
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				tags, err := repo.SetStatusCancelled(ctx, []string{_id}, _functionName)
+				assert.Equal(t, []string{_tag}, tags)
+				return err
+			},
+		},
+		{
+			name: "runs",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("select").WillReturnRows(sqlmock.NewRows([]string{"tag"}))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				_, err := repo.Runs(ctx, _tag, cronger.RunFilter{Status: cronger.Failed, Limit: 10})
+				return err
+			},
+		},
+		{
+			name: "add_run",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("insert").WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.AddRun(ctx, cronger.Run{Tag: _tag, Status: cronger.Failed, Error: _description})
+			},
+		},
+		{
+			name: "try_lock",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("select").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				ok, err := repo.TryLock(ctx, _tag, time.Now())
+				assert.True(t, ok)
+				return err
+			},
+		},
+		{
+			name: "set_dependencies",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("delete").WithArgs(_tag).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("insert").WithArgs(_tag, _id).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			call: func(repo *cronger.SqlxRepository) error {
+				return repo.SetDependencies(ctx, _tag, []string{_id})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newMockRepository(t)
+			tt.mock(mock)
+			assert.NoError(t, tt.call(repo))
+		})
+	}
+}
+
+func TestSqlxRepositoryStatementCache(t *testing.T) {
+	ctx := context.Background()
+	repo, mock := newMockRepository(t, cronger.WithStatementCache())
+
+	prepared := mock.ExpectPrepare("delete")
+	prepared.ExpectExec().WithArgs(_tag).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepared.ExpectExec().WithArgs(_tag).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepared.WillBeClosed()
+
+	assert.NoError(t, repo.Remove(ctx, _tag))
+	assert.NoError(t, repo.Remove(ctx, _tag))
+	assert.NoError(t, repo.Close())
+}
+
+// migratedJobColumns returns the columns of the jobs table after all migrations.
+func migratedJobColumns(t *testing.T) []string {
+	files, err := filepath.Glob("migration/*.sql")
+	require.NoError(t, err)
+	sort.Strings(files)
+
+	create := regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS \{\{\.Jobs\}\} \((.*?)\n\);`)
+	column := regexp.MustCompile(`ADD COLUMN IF NOT EXISTS "?(\w+)"?`)
+	alter := regexp.MustCompile(`(?s)ALTER TABLE \{\{\.Jobs\}\}[^;]*;`)
+
+	var columns []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		up := strings.SplitN(string(data), "-- +migrate Down", 2)[0]
+
+		if m := create.FindStringSubmatch(up); m != nil {
+			for _, line := range strings.Split(m[1], "\n") {
+				if fields := strings.Fields(strings.TrimSpace(line)); len(fields) != 0 {
+					columns = append(columns, strings.Trim(fields[0], `"`))
+				}
+			}
+		}
+		for _, statement := range alter.FindAllString(up, -1) {
+			for _, m := range column.FindAllStringSubmatch(statement, -1) {
+				columns = append(columns, m[1])
+			}
+		}
+	}
+	return columns
+}
+
+func newSqlmock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(
+		sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+			if !strings.Contains(strings.ToLower(actualSQL), expectedSQL) {
+				return fmt.Errorf("%q does not contain %q", actualSQL, expectedSQL)
+			}
+			return nil
+		}),
+	))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func TestSqlxRepositoryScansMigratedColumns(t *testing.T) {
+	ctx := context.Background()
+	tag := uuid.NewString()
+	id := uuid.NewString()
+	now := time.Now()
+	values := map[string]driver.Value{
+		"tag":                tag,
+		"id":                 id,
+		"expression":         "* * * * *",
+		"status":             cronger.Working.String(),
+		"status_description": "",
+		"function_name":      "send",
+		"function_fields":    []byte(`["payload"]`),
+		"limit":              1,
+		"created_at":         now,
+		"timeout":            0,
+		"max_attempts":       3,
+		"backoff":            cronger.BackoffFixed.String(),
+		"backoff_delay":      int64(time.Second),
+		"backoff_max":        0,
+		"jitter":             0.1,
+		"attempt":            1,
+		"next_attempt_at":    now,
+		"fired_at":           now,
+		"run_at":             nil,
+		"interval":           0,
+		"interval_name":      "",
+		"expression_format":  cronger.FormatStandard.String(),
+		"location":           "UTC",
+		"misfire_policy":     "",
+		"misfire_limit":      0,
+		"last_fire_at":       now,
+		"next_fire_at":       nil,
+		"overlap_policy":     "",
+		"dependency_policy":  "",
+		"fire_count":         0,
+	}
+
+	columns := migratedJobColumns(t)
+	row := make([]driver.Value, len(columns))
+	for i, column := range columns {
+		value, ok := values[column]
+		require.True(t, ok, "no value for column %s", column)
+		row[i] = value
+	}
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).AddRow(row...)
+	}
+
+	tests := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+		call func(repo *cronger.SqlxRepository) ([]cronger.Job, error)
+	}{
+		{
+			name: "jobs",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("select").WillReturnRows(rows())
+			},
+			call: func(repo *cronger.SqlxRepository) ([]cronger.Job, error) {
+				return repo.Jobs(ctx)
+			},
+		},
+		{
+			name: "jobs_by_status",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("select").WillReturnRows(rows())
+			},
+			call: func(repo *cronger.SqlxRepository) ([]cronger.Job, error) {
+				return repo.JobsByStatus(ctx, cronger.Working)
+			},
+		},
+		{
+			name: "suspend_jobs",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("select").WillReturnRows(rows())
+				mock.ExpectCommit()
+			},
+			call: func(repo *cronger.SqlxRepository) ([]cronger.Job, error) {
+				return repo.SuspendJobs(ctx)
+			},
+		},
+		{
+			name: "set_status_cancelled",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("select").WillReturnRows(rows())
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			call: func(repo *cronger.SqlxRepository) ([]cronger.Job, error) {
+				tags, err := repo.SetStatusCancelled(ctx, []string{id}, "send")
+				assert.Equal(t, []string{tag}, tags)
+				return nil, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSqlmock(t)
+			repo := cronger.NewSqlx(db)
+
+			tt.mock(mock)
+			jobs, err := tt.call(repo)
+			require.NoError(t, err)
+			if jobs == nil {
+				return
+			}
+
+			require.Len(t, jobs, 1)
+			job := jobs[0]
+			assert.Equal(t, tag, job.Tag)
+			assert.Equal(t, uint(3), job.MaxAttempts)
+			assert.Equal(t, "UTC", job.Location)
+			require.NotNil(t, job.FiredAt)
+			assert.True(t, now.Equal(*job.FiredAt))
+			assert.Nil(t, job.RunAt)
+		})
+	}
+}
+
+func TestSqlxRepositoryTryLockClaimed(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  bool
+		claimed int64
+	}{
+		{name: "locked by another instance", locked: false},
+		{name: "occurrence already claimed", locked: true, claimed: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSqlmock(t)
+			repo := cronger.NewSqlx(db)
+			mock.ExpectBegin()
+			mock.ExpectQuery("select").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(tt.locked))
+			if tt.locked {
+				mock.ExpectExec("update").WillReturnResult(sqlmock.NewResult(0, tt.claimed))
+			}
+			mock.ExpectRollback()
+
+			ok, err := repo.TryLock(context.Background(), uuid.NewString(), time.Now())
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package cronger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vladjong/cronger"
+	"github.com/vladjong/cronger/mocks"
+)
+
+func TestTimeoutAndCancellation(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(cr *cronger.Cronger, job cronger.Job) error
+		status cronger.Status
+		reason error
+	}{
+		{
+			name:   "timeout",
+			status: cronger.Failed,
+			reason: cronger.ErrJobTimeout,
+		},
+		{
+			name: "remove",
+			cancel: func(cr *cronger.Cronger, job cronger.Job) error {
+				return cr.Remove(job.Tag)
+			},
+			status: cronger.Cancelled,
+			reason: cronger.ErrJobRemoved,
+		},
+		{
+			name: "set status cancelled",
+			cancel: func(cr *cronger.Cronger, job cronger.Job) error {
+				return cr.SetStatusCancelled([]string{job.ID}, job.FunctionName)
+			},
+			status: cronger.Cancelled,
+			reason: cronger.ErrJobCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := cronger.Job{
+				Tag:            uuid.NewString(),
+				ID:             uuid.NewString(),
+				Expression:     "0 0 1 1 *",
+				FunctionName:   "test",
+				FunctionFields: cronger.FunctionFields{},
+				Limit:          1,
+				Timeout:        time.Hour,
+			}
+			if tt.cancel == nil {
+				job.Timeout = 20 * time.Millisecond
+			}
+
+			updated := make(chan map[string]interface{}, 1)
+			recorded := make(chan cronger.Run, 1)
+			repo := &mocks.Repository{}
+			repo.On("SuspendJobs", mock.Anything).Return([]cronger.Job{}, nil)
+			// Jobs are polled every minute for the done status.
+			repo.On("Jobs", mock.Anything).Return([]cronger.Job{}, nil).Maybe()
+			repo.On("Add", mock.Anything, mock.Anything).Return(nil)
+			repo.On("Remove", mock.Anything, job.Tag).Return(nil)
+			repo.On("SetStatusCancelled", mock.Anything, []string{job.ID}, job.FunctionName).Return([]string{job.Tag}, nil)
+			repo.On("Update", mock.Anything, job.Tag, mock.Anything).Run(func(args mock.Arguments) {
+				updated <- args.Get(2).(map[string]interface{})
+			}).Return(nil)
+			repo.On("AddRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				recorded <- args.Get(1).(cronger.Run)
+			}).Return(nil)
+
+			cr, err := cronger.New(&cronger.Config{
+				Loc:        time.UTC,
+				Repository: repo,
+			})
+			require.NoError(t, err)
+			require.NoError(t, cr.Add(cronger.Fields{Job: job, Task: func(context.Context) error { return nil }}))
+
+			started := make(chan struct{})
+			done := make(chan error, 1)
+			go cr.Template(job, func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				done <- context.Cause(ctx)
+				return ctx.Err()
+			})
+			<-started
+
+			if tt.cancel != nil {
+				require.NoError(t, tt.cancel(cr, job))
+			}
+			select {
+			case cause := <-done:
+				if tt.cancel != nil {
+					assert.ErrorIs(t, cause, tt.reason)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("task is not cancelled")
+			}
+
+			select {
+			case run := <-recorded:
+				assert.Equal(t, tt.status, run.Status)
+				assert.Contains(t, run.Error, tt.reason.Error())
+			case <-time.After(time.Second):
+				t.Fatal("run is not recorded")
+			}
+
+			if tt.cancel != nil {
+				// The status of an interrupted run is set by Remove or SetStatusCancelled.
+				time.Sleep(50 * time.Millisecond)
+				repo.AssertNotCalled(t, "Update", mock.Anything, job.Tag, mock.Anything)
+				return
+			}
+			select {
+			case value := <-updated:
+				assert.EqualValues(t, cronger.Failed, value["status"])
+				assert.Contains(t, value["status_description"], tt.reason.Error())
+			case <-time.After(time.Second):
+				t.Fatal("status is not updated")
+			}
+		})
+	}
+}